@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/CezaryMackowski/go-ls/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	duConfigFile   string
+	duDepth        int
+	duTop          int
+	duApparentSize bool
+	duAll          bool
+	duIgnoreVCS    bool
+)
+
+var duCmd = &cobra.Command{
+	Use:          "du",
+	Short:        "Command used to show disk usage of directory entries",
+	Example:      "go-ls du . --depth 2 --top 10",
+	Version:      "1.0.0",
+	SilenceUsage: true,
+	Args:         treeArgsParse,
+	PreRunE:      duPreRun,
+	RunE:         duRun,
+}
+
+func init() {
+	duCmd.Flags().StringVarP(&duConfigFile, "config-file", "c", "~/.config/go-ls/config.toml", "")
+	duCmd.Flags().IntVarP(&duDepth, "depth", "", 1, "number of directory levels to list")
+	duCmd.Flags().IntVarP(&duTop, "top", "", 0, "list only the N largest entries of each directory, 0 lists all")
+	duCmd.Flags().BoolVarP(&duApparentSize, "apparent-size", "", false, "use apparent sizes instead of allocated disk usage")
+	duCmd.Flags().BoolVarP(&duAll, "all", "a", false, "")
+	duCmd.Flags().BoolVarP(&duIgnoreVCS, "ignore-vcs", "", false, "respect .gitignore and .go-lsignore files")
+}
+
+func duPreRun(cmd *cobra.Command, _ []string) error {
+	var err error
+	if cmd.Flags().Changed("config-file") {
+		config, err = internal.ParseConfigFile(duConfigFile)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("all") {
+		config.Filter.All = duAll
+	}
+	if cmd.Flags().Changed("ignore-vcs") {
+		config.Filter.IgnoreVCS = duIgnoreVCS
+	}
+
+	return nil
+}
+
+func duRun(_ *cobra.Command, args []string) error {
+	root := "."
+	if len(args) != 0 {
+		root = args[0]
+	}
+
+	usage, err := internal.GetUsage(root, config, duDepth, duTop, duApparentSize)
+	if err != nil {
+		if !internal.IsPartial(err) {
+			return err
+		}
+		reportError(err, exitMinorTrouble)
+	}
+
+	fmt.Println(style.PrintUsageOutput(usage, config, duApparentSize))
+
+	return nil
+}
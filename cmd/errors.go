@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"os"
+)
+
+const (
+	exitOK             = 0
+	exitMinorTrouble   = 1
+	exitSeriousTrouble = 2
+)
+
+var exitCode = exitOK
+
+func reportError(err error, code int) {
+	var partialError *internal.PartialError
+	if errors.As(err, &partialError) {
+		for _, entryErr := range partialError.Errs {
+			fmt.Fprintln(os.Stderr, "go-ls:", entryErr)
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "go-ls:", err)
+	}
+
+	exitCode = max(exitCode, code)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/export"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/CezaryMackowski/go-ls/style"
+	"os"
+	"strings"
+)
+
+type printer interface {
+	print(header string, files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) error
+	printMore(files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) error
+	canStream() bool
+	flush() error
+}
+
+func newPrinter(showHeaders bool) (printer, error) {
+	if config.General.Format != "" {
+		writer, err := export.NewTemplateWriter(os.Stdout, config, config.General.Format)
+		if err != nil {
+			return nil, err
+		}
+
+		return &exportPrinter{writer: writer}, nil
+	}
+
+	switch config.General.Output {
+	case internal.JSON:
+		return &exportPrinter{writer: export.NewJSONWriter(os.Stdout)}, nil
+	case internal.NDJSON:
+		return &exportPrinter{writer: export.NewNDJSONWriter(os.Stdout)}, nil
+	case internal.CSV:
+		return &exportPrinter{writer: export.NewCSVWriter(os.Stdout, config, ',')}, nil
+	case internal.TSV:
+		return &exportPrinter{writer: export.NewCSVWriter(os.Stdout, config, '\t')}, nil
+	default:
+		return &textPrinter{showHeaders: showHeaders, isFirst: true}, nil
+	}
+}
+
+type exportPrinter struct {
+	writer export.Writer
+}
+
+func (p *exportPrinter) print(_ string, files []*internal.DisplayItem, _ *internal.ColumnsWidth) error {
+	return p.writer.Write(files)
+}
+
+func (p *exportPrinter) printMore(files []*internal.DisplayItem, _ *internal.ColumnsWidth) error {
+	return p.writer.Write(files)
+}
+
+func (p *exportPrinter) canStream() bool {
+	return true
+}
+
+func (p *exportPrinter) flush() error {
+	return p.writer.Flush()
+}
+
+type textPrinter struct {
+	showHeaders bool
+	isFirst     bool
+}
+
+func (p *textPrinter) print(header string, files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) error {
+	if !p.isFirst {
+		fmt.Println()
+	}
+	p.isFirst = false
+
+	if p.showHeaders && header != "" {
+		fmt.Println(header + ":")
+	}
+	if len(files) != 0 {
+		fmt.Println(render(files, columnsWidth))
+	}
+
+	return nil
+}
+
+func (p *textPrinter) printMore(files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) error {
+	if len(files) != 0 {
+		fmt.Println(render(files, columnsWidth))
+	}
+
+	return nil
+}
+
+func (p *textPrinter) canStream() bool {
+	return config.General.Long || resolveLayout() == internal.LayoutOnePerLine
+}
+
+func (p *textPrinter) flush() error {
+	return nil
+}
+
+func render(files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) string {
+	if !config.General.Long {
+		return renderShort(files)
+	}
+
+	lines := make([]string, 0, len(files))
+	for _, f := range files {
+		lines = append(lines, style.PrintLongOutput(f, config, columnsWidth))
+		if config.Filter.Xattrs && len(f.Xattrs) != 0 {
+			lines = append(lines, style.PrintXattrOutput(f, config))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderShort(files []*internal.DisplayItem) string {
+	switch resolveLayout() {
+	case internal.LayoutColumns:
+		return style.PrintGridOutput(files, config, style.TerminalWidth(), false)
+	case internal.LayoutAcross:
+		return style.PrintGridOutput(files, config, style.TerminalWidth(), true)
+	default:
+		return style.PrintOnePerLineOutput(files, config)
+	}
+}
+
+func resolveLayout() internal.Layout {
+	if config.General.Layout != internal.LayoutAuto && config.General.Layout != "" {
+		return config.General.Layout
+	}
+	if style.IsTerminal() {
+		return internal.LayoutColumns
+	}
+
+	return internal.LayoutOnePerLine
+}
@@ -1,40 +1,68 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/CezaryMackowski/go-ls/internal"
-	"github.com/CezaryMackowski/go-ls/style"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"os"
+	"time"
 )
 
 var (
 	config           = internal.NewConfig()
 	configFile       string
 	isLong           bool
+	recursive        bool
+	dereference      bool
+	numericUidGid    bool
+	concurrency      int
+	totalSize        bool
+	totalSizeDepth   int
+	totalSizeTimeout time.Duration
+	diskSize         bool
+	columns          bool
+	across           bool
+	onePerLine       bool
 	dirsFirst        bool
 	filesFirst       bool
 	fileName         bool
 	permissions      bool
+	octal            bool
 	userName         bool
 	groupName        bool
 	modificationTime bool
+	accessTime       bool
+	changeTime       bool
+	birthTime        bool
 	nLinks           bool
+	inode            bool
+	blocks           bool
+	device           bool
 	all              bool
+	almostAll        bool
+	hiddenPatterns   []string
+	ignoreVCS        bool
 	onlyDirs         bool
 	onlyFiles        bool
+	sparseness       bool
+	onlySparse       bool
+	xattrs           bool
 	dateFormat       string
 	sizeUnit         internal.SizeType
+	outputType       internal.OutputType
+	format           string
+	sortBy           internal.SortBy
+	reverse          bool
+	natural          bool
+	collation        internal.Collation
 )
 
 var RootCmd = &cobra.Command{
-	Use:          "go-ls ",
+	Use:          "go-ls [path...]",
 	Short:        "go-ls is a CLI tool to list files in local environment",
 	Example:      "go-ls",
 	Version:      "1.0.0",
 	SilenceUsage: true,
-	Args:         argsParse,
+	Args:         cobra.ArbitraryArgs,
 	PreRunE:      preRun,
 	RunE:         run,
 }
@@ -42,39 +70,63 @@ var RootCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(versionCmd)
 	RootCmd.AddCommand(generateConfigCmd)
+	RootCmd.AddCommand(treeCmd)
+	RootCmd.AddCommand(duCmd)
 
 	RootCmd.Flags().StringVarP(&configFile, "config-file", "c", "~/.config/go-ls/config.toml", "")
 	RootCmd.Flags().StringVarP(&dateFormat, "date-format", "t", "Jan 02 15:04", "")
 	RootCmd.Flags().VarP(&sizeUnit, "size-unit", "s", "")
+	RootCmd.Flags().VarP(&outputType, "output", "o", "text, json, ndjson, csv or tsv")
+	RootCmd.Flags().StringVarP(&format, "format", "", "", "text/template format or name of a template from config")
 	RootCmd.Flags().BoolVarP(&isLong, "long", "l", false, "")
+	RootCmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "")
+	RootCmd.Flags().BoolVarP(&dereference, "dereference", "L", false, "show information for the file a symbolic link references")
+	RootCmd.Flags().BoolVarP(&columns, "columns", "C", false, "list entries by columns")
+	RootCmd.Flags().BoolVarP(&across, "across", "x", false, "list entries by lines instead of by columns")
+	RootCmd.Flags().BoolVarP(&onePerLine, "one-per-line", "1", false, "list one file per line")
+	RootCmd.Flags().VarP(&sortBy, "sort", "", "comma separated keys: name, size, time, atime, ctime, btime, ext, type, dirs or none, prefixed with - to reverse")
+	RootCmd.Flags().BoolVarP(&reverse, "reverse", "", false, "")
+	RootCmd.Flags().BoolVarP(&natural, "natural", "v", false, "")
+	RootCmd.Flags().VarP(&collation, "collation", "", "bytewise, ignore-case or locale, which follows LC_ALL, LC_COLLATE or LANG")
 	RootCmd.Flags().BoolVarP(&dirsFirst, "dirs-first", "d", true, "")
 	RootCmd.Flags().BoolVarP(&filesFirst, "files-first", "f", false, "")
 	RootCmd.Flags().BoolVarP(&fileName, "filename", "n", true, "")
 	RootCmd.Flags().BoolVarP(&permissions, "permissions", "p", true, "")
+	RootCmd.Flags().BoolVarP(&octal, "octal", "", false, "show permissions in octal, e.g. 0755")
+	RootCmd.Flags().BoolVarP(&totalSize, "total-size", "", false, "show the recursive size of directories")
+	RootCmd.Flags().IntVarP(&totalSizeDepth, "total-size-depth", "", 0, "maximum depth summed by --total-size, 0 means unlimited")
+	RootCmd.Flags().DurationVarP(&totalSizeTimeout, "total-size-timeout", "", 10*time.Second, "time budget for --total-size, 0 means unlimited")
+	RootCmd.Flags().BoolVarP(&diskSize, "disk-size", "", false, "show the allocated size on disk")
+	RootCmd.Flags().IntVarP(&concurrency, "concurrency", "", 0, "number of workers collecting file metadata, 0 uses the number of CPUs")
+	RootCmd.Flags().BoolVarP(&numericUidGid, "numeric-uid-gid", "", false, "list numeric user and group IDs")
 	RootCmd.Flags().BoolVarP(&userName, "username", "u", true, "")
 	RootCmd.Flags().BoolVarP(&groupName, "groupname", "g", true, "")
 	RootCmd.Flags().BoolVarP(&modificationTime, "modification-time", "m", true, "")
+	RootCmd.Flags().BoolVarP(&accessTime, "access-time", "", false, "show the last access time")
+	RootCmd.Flags().BoolVarP(&changeTime, "change-time", "", false, "show the last status change time")
+	RootCmd.Flags().BoolVarP(&birthTime, "birth-time", "", false, "show the creation time where the file system records it")
 	RootCmd.Flags().BoolVarP(&nLinks, "n-links", "r", true, "")
-	RootCmd.Flags().BoolVarP(&all, "all", "a", true, "")
+	RootCmd.Flags().BoolVarP(&inode, "inode", "i", false, "show the inode number")
+	RootCmd.Flags().BoolVarP(&blocks, "blocks", "", false, "show the number of allocated 512-byte blocks")
+	RootCmd.Flags().BoolVarP(&device, "device", "", false, "show major:minor numbers of block and char devices")
+	RootCmd.Flags().BoolVarP(&all, "all", "a", false, "")
+	RootCmd.Flags().BoolVarP(&almostAll, "almost-all", "A", false, "")
+	RootCmd.Flags().StringArrayVarP(&hiddenPatterns, "hide", "", nil, "")
+	RootCmd.Flags().BoolVarP(&ignoreVCS, "ignore-vcs", "", false, "respect .gitignore and .go-lsignore files")
 	RootCmd.Flags().BoolVarP(&onlyDirs, "only-dirs", "", false, "")
 	RootCmd.Flags().BoolVarP(&onlyFiles, "only-files", "", false, "")
+	RootCmd.Flags().BoolVarP(&sparseness, "sparseness", "", false, "show the ratio of allocated to apparent size")
+	RootCmd.Flags().BoolVarP(&xattrs, "xattr", "", false, "list extended attribute names and values beneath each entry in long mode")
+	RootCmd.Flags().BoolVarP(&onlySparse, "only-sparse", "", false, "list only files that occupy less space on disk than their apparent size")
 	RootCmd.MarkFlagsMutuallyExclusive("dirs-first", "files-first")
+	RootCmd.MarkFlagsMutuallyExclusive("columns", "across", "one-per-line")
+	RootCmd.MarkFlagsMutuallyExclusive("all", "almost-all")
 	RootCmd.MarkFlagsMutuallyExclusive("only-dirs", "only-files")
+	RootCmd.MarkFlagsMutuallyExclusive("only-dirs", "only-sparse")
+	RootCmd.MarkFlagsMutuallyExclusive("output", "format")
 	RootCmd.SetErrPrefix("go-ls:")
 }
 
-func argsParse(cmd *cobra.Command, args []string) error {
-	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
-		return err
-	}
-
-	if _, err := internal.PathExists(args[0]); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func preRun(cmd *cobra.Command, _ []string) error {
 	var err error
 	if cmd.Flags().Changed("config-file") {
@@ -89,9 +141,49 @@ func preRun(cmd *cobra.Command, _ []string) error {
 	if cmd.Flags().Changed("size-unit") {
 		config.General.SizeUnit = sizeUnit
 	}
+	if cmd.Flags().Changed("output") {
+		config.General.Output = outputType
+	}
+	if cmd.Flags().Changed("format") {
+		config.General.Format = format
+	}
 	if cmd.Flags().Changed("long") {
 		config.General.Long = isLong
 	}
+	if cmd.Flags().Changed("recursive") {
+		config.General.Recursive = recursive
+	}
+	if cmd.Flags().Changed("sort") {
+		config.General.SortBy = sortBy
+	}
+	if _, err = config.General.SortBy.Keys(); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("reverse") {
+		config.General.Reverse = reverse
+	}
+	if cmd.Flags().Changed("natural") {
+		config.General.Natural = natural
+	}
+	if cmd.Flags().Changed("collation") {
+		config.General.Collation = collation
+	}
+	if cmd.Flags().Changed("dereference") {
+		config.General.Dereference = dereference
+	}
+	if cmd.Flags().Changed("columns") && columns {
+		config.General.Layout = internal.LayoutColumns
+	}
+	if cmd.Flags().Changed("across") && across {
+		config.General.Layout = internal.LayoutAcross
+	}
+	if cmd.Flags().Changed("one-per-line") && onePerLine {
+		config.General.Layout = internal.LayoutOnePerLine
+	}
+	// A layout flag asks for short output, unless --long was given explicitly as well.
+	if (columns || across || onePerLine) && !cmd.Flags().Changed("long") {
+		config.General.Long = false
+	}
 	if cmd.Flags().Changed("dirs-first") {
 		config.General.DirsFirst = dirsFirst
 	}
@@ -104,6 +196,30 @@ func preRun(cmd *cobra.Command, _ []string) error {
 	if cmd.Flags().Changed("permissions") {
 		config.Filter.Permissions = permissions
 	}
+	if cmd.Flags().Changed("octal") {
+		config.Filter.OctalPermissions = octal
+	}
+	if cmd.Flags().Changed("total-size") {
+		config.General.TotalSize = totalSize
+	}
+	if cmd.Flags().Changed("total-size-depth") {
+		config.General.TotalSizeDepth = totalSizeDepth
+	}
+	if cmd.Flags().Changed("total-size-timeout") {
+		config.General.TotalSizeTimeout = totalSizeTimeout.String()
+	}
+	if _, err = config.General.TotalSizeTimeoutDuration(); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("disk-size") {
+		config.Filter.DiskSize = diskSize
+	}
+	if cmd.Flags().Changed("concurrency") {
+		config.General.Concurrency = concurrency
+	}
+	if cmd.Flags().Changed("numeric-uid-gid") {
+		config.General.NumericUidGid = numericUidGid
+	}
 	if cmd.Flags().Changed("username") {
 		config.Filter.UserName = userName
 	}
@@ -113,44 +229,151 @@ func preRun(cmd *cobra.Command, _ []string) error {
 	if cmd.Flags().Changed("modification-time") {
 		config.Filter.ModificationTime = modificationTime
 	}
+	if cmd.Flags().Changed("access-time") {
+		config.Filter.AccessTime = accessTime
+	}
+	if cmd.Flags().Changed("change-time") {
+		config.Filter.ChangeTime = changeTime
+	}
+	if cmd.Flags().Changed("birth-time") {
+		config.Filter.BirthTime = birthTime
+	}
 	if cmd.Flags().Changed("n-links") {
 		config.Filter.NLinks = nLinks
 	}
+	if cmd.Flags().Changed("inode") {
+		config.Filter.Inode = inode
+	}
+	if cmd.Flags().Changed("blocks") {
+		config.Filter.Blocks = blocks
+	}
+	if cmd.Flags().Changed("device") {
+		config.Filter.Device = device
+	}
 	if cmd.Flags().Changed("all") {
 		config.Filter.All = all
 	}
+	if cmd.Flags().Changed("almost-all") {
+		config.Filter.AlmostAll = almostAll
+	}
+	if cmd.Flags().Changed("hide") {
+		config.Filter.HiddenPatterns = append(config.Filter.HiddenPatterns, hiddenPatterns...)
+	}
+	if cmd.Flags().Changed("ignore-vcs") {
+		config.Filter.IgnoreVCS = ignoreVCS
+	}
 	if cmd.Flags().Changed("only-dirs") {
 		config.Filter.OnlyDirs = onlyDirs
 	}
 	if cmd.Flags().Changed("only-files") {
 		config.Filter.OnlyFiles = onlyFiles
 	}
+	if cmd.Flags().Changed("sparseness") {
+		config.Filter.Sparseness = sparseness
+	}
+	if cmd.Flags().Changed("xattr") {
+		config.Filter.Xattrs = xattrs
+	}
+	if cmd.Flags().Changed("only-sparse") {
+		config.Filter.OnlySparse = onlySparse
+	}
 
 	return nil
 }
 
 func run(_ *cobra.Command, args []string) error {
-	var lines []string
-	var output string
-	files, columnsWidth, err := internal.GetFiles(args[0], config)
+	if err := config.General.StartTotalSizeTimer(); err != nil {
+		return err
+	}
+
+	paths, err := internal.ExpandPaths(args)
 	if err != nil {
 		return err
 	}
 
-	for _, f := range files {
-		if config.General.Long {
-			output = style.PrintLongOutput(f, config, columnsWidth)
+	files, directories, err := internal.ReadPaths(paths, config)
+	if err != nil {
+		reportError(err, exitSeriousTrouble)
+	}
+
+	printer, err := newPrinter(len(paths) > 1 || config.General.Recursive)
+	if err != nil {
+		return err
+	}
+
+	if len(files) != 0 {
+		if err = printer.print("", files, internal.NewColumnsWidth(files, config)); err != nil {
+			return err
+		}
+	}
+
+	for _, directory := range directories {
+		if config.General.Recursive {
+			err = printRecursive(printer, directory)
 		} else {
-			output = style.PrintShortOutput(f, config)
+			err = printDirectory(printer, directory)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return printer.flush()
+}
+
+func printDirectory(printer printer, path string) error {
+	if printer.canStream() && internal.IsUnsorted(config) {
+		return streamDirectory(printer, path)
+	}
+
+	files, columnsWidth, err := internal.GetFiles(path, config)
+	if err != nil {
+		if !internal.IsPartial(err) {
+			reportError(err, exitSeriousTrouble)
+			return nil
 		}
+		reportError(err, exitMinorTrouble)
+	}
+
+	return printer.print(path, files, columnsWidth)
+}
 
-		lines = append(lines, output)
+func streamDirectory(printer printer, path string) error {
+	isFirst := true
+
+	err := internal.StreamFiles(path, config, func(files []*internal.DisplayItem, columnsWidth *internal.ColumnsWidth) error {
+		if isFirst {
+			isFirst = false
+			return printer.print(path, files, columnsWidth)
+		}
+
+		return printer.printMore(files, columnsWidth)
+	})
+	if err != nil {
+		if !internal.IsPartial(err) {
+			reportError(err, exitSeriousTrouble)
+			return nil
+		}
+		reportError(err, exitMinorTrouble)
 	}
 
-	if config.General.Long {
-		fmt.Println(lipgloss.JoinVertical(lipgloss.Top, lines...))
-	} else {
-		fmt.Println(lipgloss.JoinHorizontal(lipgloss.Left, lines...))
+	return nil
+}
+
+func printRecursive(printer printer, path string) error {
+	err := internal.WalkFiles(path, config, func(directory *internal.DirectoryListing) error {
+		if directory.Err != nil {
+			if directory.Path == path && !internal.IsPartial(directory.Err) {
+				reportError(directory.Err, exitSeriousTrouble)
+			} else {
+				reportError(directory.Err, exitMinorTrouble)
+			}
+		}
+
+		return printer.print(directory.Path, directory.Files, directory.ColumnsWidth)
+	})
+	if err != nil {
+		reportError(err, exitSeriousTrouble)
 	}
 
 	return nil
@@ -158,6 +381,8 @@ func run(_ *cobra.Command, args []string) error {
 
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitSeriousTrouble)
 	}
+
+	os.Exit(exitCode)
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/CezaryMackowski/go-ls/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeConfigFile string
+	treeMaxDepth   int
+	treeLong       bool
+	treeDirsFirst  bool
+	treeIgnoreVCS  bool
+)
+
+var treeCmd = &cobra.Command{
+	Use:          "tree",
+	Short:        "Command used to list files as a tree",
+	Example:      "go-ls tree . -L 2",
+	Version:      "1.0.0",
+	SilenceUsage: true,
+	Args:         treeArgsParse,
+	PreRunE:      treePreRun,
+	RunE:         treeRun,
+}
+
+func init() {
+	treeCmd.Flags().StringVarP(&treeConfigFile, "config-file", "c", "~/.config/go-ls/config.toml", "")
+	treeCmd.Flags().IntVarP(&treeMaxDepth, "max-depth", "L", 0, "")
+	treeCmd.Flags().BoolVarP(&treeLong, "long", "l", false, "")
+	treeCmd.Flags().BoolVarP(&treeDirsFirst, "dirs-first", "d", true, "")
+	treeCmd.Flags().BoolVarP(&treeIgnoreVCS, "ignore-vcs", "", false, "respect .gitignore and .go-lsignore files")
+}
+
+func treeArgsParse(cmd *cobra.Command, args []string) error {
+	if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	if _, err := internal.PathExists(args[0]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func treePreRun(cmd *cobra.Command, _ []string) error {
+	var err error
+	if cmd.Flags().Changed("config-file") {
+		config, err = internal.ParseConfigFile(treeConfigFile)
+		if err != nil {
+			return err
+		}
+	}
+	config.General.Long = treeLong
+	if cmd.Flags().Changed("dirs-first") {
+		config.General.DirsFirst = treeDirsFirst
+	}
+	if cmd.Flags().Changed("ignore-vcs") {
+		config.Filter.IgnoreVCS = treeIgnoreVCS
+	}
+
+	return nil
+}
+
+func treeRun(_ *cobra.Command, args []string) error {
+	root := "."
+	if len(args) != 0 {
+		root = args[0]
+	}
+
+	nodes, columnsWidth, err := internal.GetTree(root, config, treeMaxDepth)
+	if err != nil {
+		if !internal.IsPartial(err) {
+			return err
+		}
+		reportError(err, exitMinorTrouble)
+	}
+
+	fmt.Println(style.PrintTreeOutput(root, nodes, config, columnsWidth, treeLong))
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"encoding/csv"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvColumn struct {
+	header string
+	value  func(file *internal.DisplayItem) string
+}
+
+type CSVWriter struct {
+	writer        *csv.Writer
+	columns       []csvColumn
+	headerWritten bool
+}
+
+func NewCSVWriter(w io.Writer, config *internal.Config, comma rune) *CSVWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	return &CSVWriter{
+		writer:  writer,
+		columns: csvColumns(config),
+	}
+}
+
+func (c *CSVWriter) Write(files []*internal.DisplayItem) error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(c.columns))
+	for _, f := range files {
+		for i, column := range c.columns {
+			record[i] = column.value(f)
+		}
+
+		if err := c.writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
+
+func (c *CSVWriter) Flush() error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
+
+func (c *CSVWriter) writeHeader() error {
+	c.headerWritten = true
+
+	header := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		header[i] = column.header
+	}
+
+	return c.writer.Write(header)
+}
+
+func csvColumns(config *internal.Config) []csvColumn {
+	columns := make([]csvColumn, 0)
+
+	if config.Filter.Inode {
+		columns = append(columns, csvColumn{header: "inode", value: func(file *internal.DisplayItem) string {
+			return strconv.FormatUint(file.Inode, 10)
+		}})
+	}
+	if config.Filter.Blocks {
+		columns = append(columns, csvColumn{header: "blocks", value: func(file *internal.DisplayItem) string {
+			return strconv.FormatInt(file.Blocks, 10)
+		}})
+	}
+
+	if config.Filter.Permissions {
+		columns = append(columns, csvColumn{header: "permissions", value: func(file *internal.DisplayItem) string {
+			return file.Permissions
+		}})
+	}
+	if config.Filter.OctalPermissions {
+		columns = append(columns, csvColumn{header: "octal_permissions", value: func(file *internal.DisplayItem) string {
+			return internal.FormatOctalMode(file.Mode)
+		}})
+	}
+	if config.Filter.NLinks {
+		columns = append(columns, csvColumn{header: "n_links", value: func(file *internal.DisplayItem) string {
+			return file.NLinks
+		}})
+	}
+	if config.Filter.UserName {
+		columns = append(columns, csvColumn{header: "user_name", value: func(file *internal.DisplayItem) string {
+			return file.UserName
+		}})
+	}
+	if config.Filter.GroupName {
+		columns = append(columns, csvColumn{header: "group_name", value: func(file *internal.DisplayItem) string {
+			return file.GroupName
+		}})
+	}
+	if config.General.SizeUnit != internal.None {
+		columns = append(columns, csvColumn{header: "size", value: func(file *internal.DisplayItem) string {
+			return strconv.FormatInt(file.Size, 10)
+		}})
+	}
+	if config.Filter.DiskSize {
+		columns = append(columns, csvColumn{header: "disk_size", value: func(file *internal.DisplayItem) string {
+			return strconv.FormatInt(file.DiskSize, 10)
+		}})
+	}
+	if config.Filter.Sparseness {
+		columns = append(columns, csvColumn{header: "sparseness", value: func(file *internal.DisplayItem) string {
+			return internal.FormatSparseness(file)
+		}})
+	}
+	if config.Filter.Device {
+		columns = append(columns, csvColumn{header: "device", value: func(file *internal.DisplayItem) string {
+			return internal.FormatDevice(file)
+		}})
+	}
+	if config.Filter.ModificationTime {
+		columns = append(columns, csvColumn{header: "modification_time", value: func(file *internal.DisplayItem) string {
+			return formatTime(file.ModTime)
+		}})
+	}
+	if config.Filter.AccessTime {
+		columns = append(columns, csvColumn{header: "access_time", value: func(file *internal.DisplayItem) string {
+			return formatTime(file.AccessTime)
+		}})
+	}
+	if config.Filter.ChangeTime {
+		columns = append(columns, csvColumn{header: "change_time", value: func(file *internal.DisplayItem) string {
+			return formatTime(file.ChangeTime)
+		}})
+	}
+	if config.Filter.BirthTime {
+		columns = append(columns, csvColumn{header: "birth_time", value: func(file *internal.DisplayItem) string {
+			return formatTime(file.BirthTime)
+		}})
+	}
+	if config.Filter.FileName {
+		columns = append(columns, csvColumn{header: "file_name", value: func(file *internal.DisplayItem) string {
+			return file.Name
+		}})
+	}
+
+	return columns
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"encoding/json"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"io"
+	"time"
+)
+
+type Writer interface {
+	Write(files []*internal.DisplayItem) error
+	Flush() error
+}
+
+type JSONItem struct {
+	Name       string       `json:"name"`
+	Path       string       `json:"path"`
+	Type       string       `json:"type"`
+	Size       int64        `json:"size"`
+	DiskSize   int64        `json:"disk_size"`
+	Truncated  bool         `json:"size_truncated,omitempty"`
+	Inode      uint64       `json:"inode"`
+	Blocks     int64        `json:"blocks"`
+	Device     string       `json:"device,omitempty"`
+	Mode       uint32       `json:"mode"`
+	NLinks     uint64       `json:"n_links"`
+	Uid        uint32       `json:"uid"`
+	Gid        uint32       `json:"gid"`
+	UserName   string       `json:"user_name"`
+	GroupName  string       `json:"group_name"`
+	ModifiedAt time.Time    `json:"modified_at"`
+	AccessedAt time.Time    `json:"accessed_at"`
+	ChangedAt  time.Time    `json:"changed_at"`
+	BornAt     *time.Time   `json:"born_at,omitempty"`
+	LinkTarget string       `json:"link_target,omitempty"`
+	LinkStatus string       `json:"link_status,omitempty"`
+	Xattrs     []*JSONXattr `json:"xattrs,omitempty"`
+}
+
+type JSONXattr struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+func NewJSONItem(file *internal.DisplayItem) *JSONItem {
+	item := &JSONItem{
+		Name:       file.Name,
+		Path:       file.Path,
+		Type:       file.Type.String(),
+		Size:       file.Size,
+		DiskSize:   file.DiskSize,
+		Truncated:  file.SizeTruncated,
+		Inode:      file.Inode,
+		Blocks:     file.Blocks,
+		Mode:       internal.ModeBits(file.Mode),
+		NLinks:     file.LinkCount,
+		Uid:        file.Uid,
+		Gid:        file.Gid,
+		UserName:   file.UserName,
+		GroupName:  file.GroupName,
+		ModifiedAt: file.ModTime,
+		AccessedAt: file.AccessTime,
+		ChangedAt:  file.ChangeTime,
+	}
+	if !file.BirthTime.IsZero() {
+		item.BornAt = &file.BirthTime
+	}
+	if file.IsDevice() {
+		item.Device = internal.FormatDevice(file)
+	}
+	for _, xattr := range file.Xattrs {
+		jsonXattr := &JSONXattr{Name: xattr.Name}
+		if xattr.Value != "" {
+			jsonXattr.Value = internal.FormatXattrValue(xattr.Value)
+		}
+		item.Xattrs = append(item.Xattrs, jsonXattr)
+	}
+	if file.IsLink() {
+		item.LinkTarget = file.LinkTarget
+		item.LinkStatus = file.LinkStatus.String()
+	}
+
+	return item
+}
+
+type JSONWriter struct {
+	w     io.Writer
+	items []*JSONItem
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{
+		w:     w,
+		items: make([]*JSONItem, 0),
+	}
+}
+
+func (j *JSONWriter) Write(files []*internal.DisplayItem) error {
+	for _, f := range files {
+		j.items = append(j.items, NewJSONItem(f))
+	}
+
+	return nil
+}
+
+func (j *JSONWriter) Flush() error {
+	encoder := json.NewEncoder(j.w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(j.items)
+}
+
+type NDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (n *NDJSONWriter) Write(files []*internal.DisplayItem) error {
+	for _, f := range files {
+		if err := n.encoder.Encode(NewJSONItem(f)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *NDJSONWriter) Flush() error {
+	return nil
+}
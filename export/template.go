@@ -0,0 +1,73 @@
+package export
+
+import (
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/charmbracelet/lipgloss"
+	"io"
+	"io/fs"
+	"text/template"
+	"time"
+)
+
+type TemplateWriter struct {
+	w        io.Writer
+	template *template.Template
+}
+
+func NewTemplateWriter(w io.Writer, config *internal.Config, format string) (*TemplateWriter, error) {
+	if namedFormat, ok := config.Templates[format]; ok {
+		format = namedFormat
+	}
+
+	tmpl, err := template.New("format").Funcs(templateFuncs(config)).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse format: %w", err)
+	}
+
+	return &TemplateWriter{
+		w:        w,
+		template: tmpl,
+	}, nil
+}
+
+func (t *TemplateWriter) Write(files []*internal.DisplayItem) error {
+	for _, f := range files {
+		if err := t.template.Execute(t.w, f); err != nil {
+			return fmt.Errorf("failed to execute format: %w", err)
+		}
+
+		if _, err := fmt.Fprintln(t.w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TemplateWriter) Flush() error {
+	return nil
+}
+
+func templateFuncs(config *internal.Config) template.FuncMap {
+	return template.FuncMap{
+		"size": func(bytes int64, unit ...string) string {
+			if len(unit) != 0 {
+				return internal.SizeFormat(bytes, internal.SizeType(unit[0]))
+			}
+
+			return internal.SizeFormat(bytes, config.General.SizeUnit)
+		},
+		"color": func(foreground string, text any) string {
+			return lipgloss.NewStyle().
+				Foreground(lipgloss.Color(foreground)).
+				Render(fmt.Sprint(text))
+		},
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"mode": func(mode fs.FileMode) string {
+			return internal.FormatMode(mode)
+		},
+	}
+}
@@ -6,6 +6,8 @@ require (
 	github.com/charmbracelet/lipgloss v1.0.0
 	github.com/pelletier/go-toml/v2 v2.2.3
 	github.com/spf13/cobra v1.8.1
+	golang.org/x/sys v0.19.0
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -18,5 +20,4 @@ require (
 	github.com/muesli/termenv v0.15.2 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.19.0 // indirect
 )
@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"fmt"
 	"github.com/pelletier/go-toml/v2"
 	"os"
+	"strings"
+	"time"
 )
 
 type SizeType string
@@ -29,6 +32,145 @@ const (
 	Auto               = "auto"
 )
 
+type OutputType string
+
+func (o *OutputType) String() string {
+	return string(*o)
+}
+
+func (o *OutputType) Set(s string) error {
+	switch OutputType(s) {
+	case Text, JSON, NDJSON, CSV, TSV:
+		*o = OutputType(s)
+		return nil
+	default:
+		return fmt.Errorf("must be one of %s, %s, %s, %s, %s", Text, JSON, NDJSON, CSV, TSV)
+	}
+}
+
+func (o *OutputType) Type() string {
+	return "outputType"
+}
+
+const (
+	Text   OutputType = "text"
+	JSON   OutputType = "json"
+	NDJSON OutputType = "ndjson"
+	CSV    OutputType = "csv"
+	TSV    OutputType = "tsv"
+)
+
+type SortBy string
+
+func (s *SortBy) String() string {
+	return string(*s)
+}
+
+func (s *SortBy) Set(s2 string) error {
+	if _, err := SortBy(s2).Keys(); err != nil {
+		return err
+	}
+
+	*s = SortBy(s2)
+	return nil
+}
+
+func (s *SortBy) Type() string {
+	return "sortBy"
+}
+
+func (s SortBy) Keys() ([]SortKey, error) {
+	if s == "" {
+		return []SortKey{{Field: SortByName}}, nil
+	}
+
+	keys := make([]SortKey, 0)
+	for _, field := range strings.Split(string(s), ",") {
+		field = strings.TrimSpace(field)
+		key := SortKey{Field: SortBy(strings.TrimPrefix(field, "-")), Reverse: strings.HasPrefix(field, "-")}
+
+		switch key.Field {
+		case SortByName, SortBySize, SortByTime, SortByAccessTime, SortByChangeTime, SortByBirthTime, SortByExtension, SortByType, SortByDirs, SortByNone:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unknown sort key %q, must be one of %s, %s, %s, %s, %s, %s, %s, %s, %s, %s", field, SortByName, SortBySize, SortByTime, SortByAccessTime, SortByChangeTime, SortByBirthTime, SortByExtension, SortByType, SortByDirs, SortByNone)
+		}
+	}
+
+	return keys, nil
+}
+
+const (
+	SortByName       SortBy = "name"
+	SortBySize       SortBy = "size"
+	SortByTime       SortBy = "time"
+	SortByAccessTime SortBy = "atime"
+	SortByChangeTime SortBy = "ctime"
+	SortByBirthTime  SortBy = "btime"
+	SortByExtension  SortBy = "ext"
+	SortByType       SortBy = "type"
+	SortByDirs       SortBy = "dirs"
+	SortByNone       SortBy = "none"
+)
+
+type SortKey struct {
+	Field   SortBy
+	Reverse bool
+}
+
+type Collation string
+
+func (c *Collation) String() string {
+	return string(*c)
+}
+
+func (c *Collation) Set(s string) error {
+	switch Collation(s) {
+	case CollationBytewise, CollationIgnoreCase, CollationLocale:
+		*c = Collation(s)
+		return nil
+	default:
+		return fmt.Errorf("must be one of %s, %s, %s", CollationBytewise, CollationIgnoreCase, CollationLocale)
+	}
+}
+
+func (c *Collation) Type() string {
+	return "collation"
+}
+
+const (
+	CollationBytewise   Collation = "bytewise"
+	CollationIgnoreCase Collation = "ignore-case"
+	CollationLocale     Collation = "locale"
+)
+
+type Layout string
+
+func (l *Layout) String() string {
+	return string(*l)
+}
+
+func (l *Layout) Set(s string) error {
+	switch Layout(s) {
+	case LayoutAuto, LayoutColumns, LayoutAcross, LayoutOnePerLine:
+		*l = Layout(s)
+		return nil
+	default:
+		return fmt.Errorf("must be one of %s, %s, %s, %s", LayoutAuto, LayoutColumns, LayoutAcross, LayoutOnePerLine)
+	}
+}
+
+func (l *Layout) Type() string {
+	return "layout"
+}
+
+const (
+	LayoutAuto       Layout = "auto"
+	LayoutColumns    Layout = "columns"
+	LayoutAcross     Layout = "across"
+	LayoutOnePerLine Layout = "one-per-line"
+)
+
 type Color struct {
 	Foreground string `toml:"foreground"`
 	Background string `toml:"background"`
@@ -48,6 +190,12 @@ type Permissions struct {
 	OthersReadColor    Color `toml:"others_read_color"`
 	OthersWriteColor   Color `toml:"others_write_color"`
 	OthersExecuteColor Color `toml:"others_execute_color"`
+
+	SetuidColor Color `toml:"setuid_color"`
+	SetgidColor Color `toml:"setgid_color"`
+	StickyColor Color `toml:"sticky_color"`
+
+	XattrColor Color `toml:"xattr_color"`
 }
 
 type FileName struct {
@@ -59,6 +207,8 @@ type FileName struct {
 	BlockDeviceColor  Color `toml:"block_device_color"`
 	CharDeviceColor   Color `toml:"char_device_color"`
 	SocketColor       Color `toml:"socket_color"`
+	BrokenLinkColor   Color `toml:"broken_link_color"`
+	LinkLoopColor     Color `toml:"link_loop_color"`
 }
 
 type UserName struct {
@@ -67,67 +217,167 @@ type UserName struct {
 }
 
 type General struct {
-	Long       bool     `toml:"long"`
-	DirsFirst  bool     `toml:"dirs_first"`
-	FilesFirst bool     `toml:"files_first"`
-	DateFormat string   `toml:"date_format"`
-	SizeUnit   SizeType `toml:"size_unit"`
+	Long             bool       `toml:"long"`
+	Recursive        bool       `toml:"recursive"`
+	Dereference      bool       `toml:"dereference"`
+	NumericUidGid    bool       `toml:"numeric_uid_gid"`
+	Concurrency      int        `toml:"concurrency"`
+	TotalSize        bool       `toml:"total_size"`
+	TotalSizeDepth   int        `toml:"total_size_depth"`
+	TotalSizeTimeout string     `toml:"total_size_timeout"`
+	Layout           Layout     `toml:"layout"`
+	DirsFirst        bool       `toml:"dirs_first"`
+	FilesFirst       bool       `toml:"files_first"`
+	SortBy           SortBy     `toml:"sort_by"`
+	Reverse          bool       `toml:"reverse"`
+	Natural          bool       `toml:"natural_sort"`
+	Collation        Collation  `toml:"collation"`
+	DateFormat       string     `toml:"date_format"`
+	SizeUnit         SizeType   `toml:"size_unit"`
+	Output           OutputType `toml:"output"`
+	Format           string     `toml:"format"`
+
+	TotalSizeDeadline time.Time `toml:"-"`
+}
+
+func (g *General) TotalSizeTimeoutDuration() (time.Duration, error) {
+	if g.TotalSizeTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(g.TotalSizeTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid total_size_timeout %q: %w", g.TotalSizeTimeout, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid total_size_timeout %q: must not be negative", g.TotalSizeTimeout)
+	}
+
+	return timeout, nil
+}
+
+// StartTotalSizeTimer sets the deadline shared by every total size computed in this run.
+func (g *General) StartTotalSizeTimer() error {
+	timeout, err := g.TotalSizeTimeoutDuration()
+	if err != nil {
+		return err
+	}
+
+	g.TotalSizeDeadline = time.Time{}
+	if timeout > 0 {
+		g.TotalSizeDeadline = time.Now().Add(timeout)
+	}
+
+	return nil
 }
 
 type Filter struct {
-	FileName         bool `toml:"file_name"`
-	Permissions      bool `toml:"permissions"`
-	UserName         bool `toml:"user_name"`
-	GroupName        bool `toml:"group_name"`
-	ModificationTime bool `toml:"modification_time"`
-	NLinks           bool `toml:"n_links"`
-	All              bool `toml:"all"`
-	OnlyDirs         bool `toml:"only_dirs"`
-	OnlyFiles        bool `toml:"only_files"`
+	FileName         bool     `toml:"file_name"`
+	Permissions      bool     `toml:"permissions"`
+	OctalPermissions bool     `toml:"octal_permissions"`
+	UserName         bool     `toml:"user_name"`
+	GroupName        bool     `toml:"group_name"`
+	ModificationTime bool     `toml:"modification_time"`
+	AccessTime       bool     `toml:"access_time"`
+	ChangeTime       bool     `toml:"change_time"`
+	BirthTime        bool     `toml:"birth_time"`
+	NLinks           bool     `toml:"n_links"`
+	Inode            bool     `toml:"inode"`
+	Blocks           bool     `toml:"blocks"`
+	Device           bool     `toml:"device"`
+	All              bool     `toml:"all"`
+	AlmostAll        bool     `toml:"almost_all"`
+	HiddenPatterns   []string `toml:"hidden_patterns"`
+	IgnoreVCS        bool     `toml:"ignore_vcs"`
+	DiskSize         bool     `toml:"disk_size"`
+	OnlyDirs         bool     `toml:"only_dirs"`
+	OnlyFiles        bool     `toml:"only_files"`
+	Sparseness       bool     `toml:"sparseness"`
+	OnlySparse       bool     `toml:"only_sparse"`
+	Xattrs           bool     `toml:"xattrs"`
 }
 
 type Theme struct {
 	NLinks           Color        `toml:"n_links"`
+	Inode            Color        `toml:"inode"`
 	UserName         Color        `toml:"user_name"`
 	GroupName        Color        `toml:"group_name"`
 	Size             Color        `toml:"size"`
 	ModificationTime Color        `toml:"modification_time"`
+	AccessTime       Color        `toml:"access_time"`
+	ChangeTime       Color        `toml:"change_time"`
+	BirthTime        Color        `toml:"birth_time"`
+	UsageBar         Color        `toml:"usage_bar"`
 	Permissions      *Permissions `toml:"permissions"`
 	FileName         *FileName    `toml:"file_name"`
 }
 
 type Config struct {
-	General *General `toml:"general"`
-	Filter  *Filter  `toml:"filter"`
-	Theme   *Theme   `toml:"theme"`
+	General   *General          `toml:"general"`
+	Filter    *Filter           `toml:"filter"`
+	Theme     *Theme            `toml:"theme"`
+	Templates map[string]string `toml:"templates"`
 }
 
 func NewConfig() *Config {
 	return &Config{
 		General: &General{
-			Long:       true,
-			DirsFirst:  true,
-			FilesFirst: false,
-			DateFormat: "Jan 02 15:04",
-			SizeUnit:   Auto,
+			Long:             true,
+			Recursive:        false,
+			Dereference:      false,
+			NumericUidGid:    false,
+			Concurrency:      0,
+			TotalSize:        false,
+			TotalSizeDepth:   0,
+			TotalSizeTimeout: "10s",
+			Layout:           LayoutAuto,
+			DirsFirst:        true,
+			FilesFirst:       false,
+			SortBy:           SortByName,
+			Reverse:          false,
+			Natural:          false,
+			Collation:        CollationBytewise,
+			DateFormat:       "Jan 02 15:04",
+			SizeUnit:         Auto,
+			Output:           Text,
+			Format:           "",
 		},
 		Filter: &Filter{
 			FileName:         true,
 			Permissions:      true,
+			OctalPermissions: false,
 			UserName:         true,
 			GroupName:        true,
 			ModificationTime: true,
+			AccessTime:       false,
+			ChangeTime:       false,
+			BirthTime:        false,
 			NLinks:           true,
-			All:              true,
+			Inode:            false,
+			Blocks:           false,
+			Device:           false,
+			All:              false,
+			AlmostAll:        false,
+			HiddenPatterns:   []string{},
+			IgnoreVCS:        false,
+			DiskSize:         false,
 			OnlyDirs:         false,
 			OnlyFiles:        false,
+			Sparseness:       false,
+			OnlySparse:       false,
+			Xattrs:           false,
 		},
 		Theme: &Theme{
 			NLinks:           Color{Foreground: "#D9D9D9", Background: ""},
+			Inode:            Color{Foreground: "#87AFAF", Background: ""},
 			UserName:         Color{Foreground: "#EAEAC6", Background: ""},
 			GroupName:        Color{Foreground: "#D4D584", Background: ""},
 			Size:             Color{Foreground: "#FAF9D3", Background: ""},
 			ModificationTime: Color{Foreground: "#00FF02", Background: ""},
+			AccessTime:       Color{Foreground: "#00D7AF", Background: ""},
+			ChangeTime:       Color{Foreground: "#FFAF00", Background: ""},
+			BirthTime:        Color{Foreground: "#AF87FF", Background: ""},
+			UsageBar:         Color{Foreground: "#05AEFF", Background: ""},
 			Permissions: &Permissions{
 				EmptyColor:         Color{Foreground: "#C67D7D", Background: "#1825FF"},
 				OwnerReadColor:     Color{Foreground: "#55BE57", Background: ""},
@@ -139,6 +389,10 @@ func NewConfig() *Config {
 				OthersReadColor:    Color{Foreground: "#55BE57", Background: ""},
 				OthersWriteColor:   Color{Foreground: "#C1C27B", Background: ""},
 				OthersExecuteColor: Color{Foreground: "#F4005F", Background: ""},
+				SetuidColor:        Color{Foreground: "#FFFFFF", Background: "#AF0000"},
+				SetgidColor:        Color{Foreground: "#000000", Background: "#D7AF00"},
+				StickyColor:        Color{Foreground: "#FFFFFF", Background: "#005FD7"},
+				XattrColor:         Color{Foreground: "#AFAFAF", Background: ""},
 			},
 			FileName: &FileName{
 				NonRegularColor:   Color{Foreground: "#FC971E", Background: ""},
@@ -149,6 +403,8 @@ func NewConfig() *Config {
 				BlockDeviceColor:  Color{Foreground: "#FC971E", Background: ""},
 				CharDeviceColor:   Color{Foreground: "#FC971E", Background: ""},
 				SocketColor:       Color{Foreground: "#FC971E", Background: ""},
+				BrokenLinkColor:   Color{Foreground: "#FF5F5F", Background: ""},
+				LinkLoopColor:     Color{Foreground: "#FF5F5F", Background: "#5F0000"},
 			},
 		},
 	}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+type UsageNode struct {
+	Name     string
+	Path     string
+	Type     FileType
+	Usage    Usage
+	Children []*UsageNode
+}
+
+func GetUsage(path string, config *Config, maxDepth int, top int, isApparent bool) (*UsageNode, error) {
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	ignoreRules, err := newIgnoreRules(path, config)
+	if err != nil {
+		return nil, err
+	}
+
+	walker := &usageWalker{
+		config: config,
+		seen:   make(map[inodeKey]struct{}),
+		filter: true,
+	}
+
+	root := &UsageNode{
+		Name: path,
+		Path: path,
+		Type: typeOfFile(fileInfo),
+	}
+	errs := make([]error, 0)
+	walker.walkNode(root, fileInfo, 1, maxDepth, top, isApparent, ignoreRules, &errs)
+
+	return root, newPartialError(errs)
+}
+
+func (w *usageWalker) walkNode(node *UsageNode, fileInfo os.FileInfo, depth int, maxDepth int, top int, isApparent bool, ignoreRules *IgnoreRules, errs *[]error) {
+	node.Usage = w.usageOf(fileInfo)
+	if !fileInfo.IsDir() {
+		return
+	}
+
+	entries, err := os.ReadDir(node.Path)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("failed to read directory: %w", err))
+		if len(entries) == 0 {
+			return
+		}
+	}
+
+	children := make([]*UsageNode, 0, len(entries))
+	for _, entry := range entries {
+		entryPath := filepath.Join(node.Path, entry.Name())
+		if w.isSkipped(entryPath, entry, ignoreRules) {
+			continue
+		}
+
+		entryInfo, err := entry.Info()
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("failed to get file info: %w", err))
+			continue
+		}
+
+		childIgnoreRules := ignoreRules
+		if entry.IsDir() {
+			if childIgnoreRules, err = ignoreRules.child(entryPath); err != nil {
+				*errs = append(*errs, err)
+				continue
+			}
+		}
+
+		child := &UsageNode{
+			Name: entry.Name(),
+			Path: entryPath,
+			Type: typeOfFile(entryInfo),
+		}
+		if depth < maxDepth {
+			w.walkNode(child, entryInfo, depth+1, maxDepth, top, isApparent, childIgnoreRules, errs)
+		} else if entry.IsDir() {
+			child.Usage = w.walk(entryPath, depth+1, childIgnoreRules, errs)
+		} else {
+			child.Usage = w.usageOf(entryInfo)
+		}
+
+		node.Usage.add(child.Usage)
+		children = append(children, child)
+	}
+
+	slices.SortStableFunc(children, func(a, b *UsageNode) int {
+		if c := cmp.Compare(b.Usage.Size(isApparent), a.Usage.Size(isApparent)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if top > 0 && len(children) > top {
+		children = children[:top]
+	}
+
+	node.Children = children
+}
+
+func (u Usage) Size(isApparent bool) int64 {
+	if isApparent {
+		return u.Apparent
+	}
+
+	return u.Disk
+}
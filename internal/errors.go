@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+type PartialError struct {
+	Errs []error
+}
+
+func newPartialError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &PartialError{Errs: errs}
+}
+
+func (e *PartialError) Error() string {
+	messages := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e *PartialError) Unwrap() []error {
+	return e.Errs
+}
+
+func IsPartial(err error) bool {
+	var partialError *PartialError
+	return errors.As(err, &partialError)
+}
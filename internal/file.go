@@ -3,13 +3,14 @@ package internal
 import (
 	"errors"
 	"fmt"
+	"golang.org/x/sys/unix"
 	"io/fs"
 	"os"
-	"os/user"
+	"path/filepath"
 	"slices"
-	"sort"
 	"strconv"
 	"syscall"
+	"time"
 )
 
 type FileType uint8
@@ -25,15 +26,61 @@ const (
 	Socket
 )
 
+func (f FileType) String() string {
+	switch f {
+	case Regular:
+		return "regular"
+	case Directory:
+		return "directory"
+	case Pipe:
+		return "pipe"
+	case SymbolicLink:
+		return "symbolic_link"
+	case BlockDevice:
+		return "block_device"
+	case CharDevice:
+		return "char_device"
+	case Socket:
+		return "socket"
+	default:
+		return "non_regular"
+	}
+}
+
 type DisplayItem struct {
-	Name        string
-	Permissions string
-	UserName    string
-	GroupName   string
-	ModifiedAt  string
-	NLinks      string
-	Size        int64
-	Type        FileType
+	Name           string
+	Path           string
+	Permissions    string
+	UserName       string
+	GroupName      string
+	ModifiedAt     string
+	AccessedAt     string
+	ChangedAt      string
+	BornAt         string
+	NLinks         string
+	Size           int64
+	Type           FileType
+	Mode           fs.FileMode
+	Uid            uint32
+	Gid            uint32
+	LinkCount      uint64
+	ModTime        time.Time
+	AccessTime     time.Time
+	ChangeTime     time.Time
+	BirthTime      time.Time
+	DiskSize       int64
+	Inode          uint64
+	Blocks         int64
+	DeviceMajor    uint32
+	DeviceMinor    uint32
+	Xattrs         []Xattr
+	XattrIndicator string
+
+	SizeTruncated bool
+
+	LinkTarget     string
+	LinkTargetType FileType
+	LinkStatus     LinkStatus
 }
 
 type DisplayItems []*DisplayItem
@@ -50,153 +97,355 @@ func (d DisplayItems) filterFiles() DisplayItems {
 	})
 }
 
-type ByDirs []*DisplayItem
-
-func (d ByDirs) Len() int {
-	return len(d)
+type ColumnsWidth struct {
+	LenInode            int
+	LenBlocks           int
+	LenPermissions      int
+	LenOctalPermissions int
+	LenNLinks           int
+	LenUserName         int
+	LenGroupName        int
+	LenSize             int
+	LenDiskSize         int
+	LenDevice           int
+	LenSparseness       int
+	LenModifiedAt       int
+	LenAccessedAt       int
+	LenChangedAt        int
+	LenBornAt           int
+	LenFileName         int
 }
 
-func (d ByDirs) Less(i, j int) bool {
-	if d[i].Type == Directory && d[j].Type != Directory {
-		return true
+func newColumnsWidth() *ColumnsWidth {
+	return &ColumnsWidth{
+		LenInode:            0,
+		LenBlocks:           0,
+		LenPermissions:      0,
+		LenOctalPermissions: 0,
+		LenNLinks:           0,
+		LenUserName:         0,
+		LenGroupName:        0,
+		LenSize:             0,
+		LenDiskSize:         0,
+		LenDevice:           0,
+		LenSparseness:       0,
+		LenModifiedAt:       0,
+		LenAccessedAt:       0,
+		LenChangedAt:        0,
+		LenBornAt:           0,
+		LenFileName:         0,
 	}
-	if d[i].Type != Directory && d[j].Type == Directory {
-		return false
+}
+
+func NewColumnsWidth(listOfFiles []*DisplayItem, config *Config) *ColumnsWidth {
+	columnsWidth := newColumnsWidth()
+	for _, f := range listOfFiles {
+		columnsWidth.update(f, config)
 	}
 
-	return d[i].Name < d[j].Name
+	return columnsWidth
 }
 
-func (d ByDirs) Swap(i, j int) {
-	d[i], d[j] = d[j], d[i]
+func (cw *ColumnsWidth) update(file *DisplayItem, config *Config) {
+	if config.Filter.Inode && len(strconv.FormatUint(file.Inode, 10)) > cw.LenInode {
+		cw.LenInode = len(strconv.FormatUint(file.Inode, 10))
+	}
+	if config.Filter.Blocks && len(strconv.FormatInt(file.Blocks, 10)) > cw.LenBlocks {
+		cw.LenBlocks = len(strconv.FormatInt(file.Blocks, 10))
+	}
+	if config.Filter.Permissions && len(file.Permissions)+len(file.XattrIndicator) > cw.LenPermissions {
+		cw.LenPermissions = len(file.Permissions) + len(file.XattrIndicator)
+	}
+	if config.Filter.OctalPermissions && len(FormatOctalMode(file.Mode)) > cw.LenOctalPermissions {
+		cw.LenOctalPermissions = len(FormatOctalMode(file.Mode))
+	}
+	if config.Filter.NLinks && len(file.NLinks) > cw.LenNLinks {
+		cw.LenNLinks = len(file.NLinks)
+	}
+	if config.Filter.UserName && len(file.UserName) > cw.LenUserName {
+		cw.LenUserName = len(file.UserName)
+	}
+	if config.Filter.GroupName && len(file.GroupName) > cw.LenGroupName {
+		cw.LenGroupName = len(file.GroupName)
+	}
+	if config.General.SizeUnit != None && len(FormatFileSize(file, config.General.SizeUnit)) > cw.LenSize {
+		cw.LenSize = len(FormatFileSize(file, config.General.SizeUnit))
+	}
+	if config.Filter.DiskSize && len(FormatDiskSize(file, config.General.SizeUnit)) > cw.LenDiskSize {
+		cw.LenDiskSize = len(FormatDiskSize(file, config.General.SizeUnit))
+	}
+	if config.Filter.Device && len(FormatDevice(file)) > cw.LenDevice {
+		cw.LenDevice = len(FormatDevice(file))
+	}
+	if config.Filter.Sparseness && len(FormatSparseness(file)) > cw.LenSparseness {
+		cw.LenSparseness = len(FormatSparseness(file))
+	}
+	if config.Filter.ModificationTime && len(file.ModifiedAt) > cw.LenModifiedAt {
+		cw.LenModifiedAt = len(file.ModifiedAt)
+	}
+	if config.Filter.AccessTime && len(file.AccessedAt) > cw.LenAccessedAt {
+		cw.LenAccessedAt = len(file.AccessedAt)
+	}
+	if config.Filter.ChangeTime && len(file.ChangedAt) > cw.LenChangedAt {
+		cw.LenChangedAt = len(file.ChangedAt)
+	}
+	if config.Filter.BirthTime && len(file.BornAt) > cw.LenBornAt {
+		cw.LenBornAt = len(file.BornAt)
+	}
+	if config.Filter.FileName && len(file.DisplayName()) > cw.LenFileName {
+		cw.LenFileName = len(file.DisplayName())
+	}
 }
 
-type ByFiles []*DisplayItem
-
-func (d ByFiles) Len() int {
-	return len(d)
+type DirectoryListing struct {
+	Path         string
+	Files        []*DisplayItem
+	ColumnsWidth *ColumnsWidth
+	Err          error
 }
 
-func (d ByFiles) Less(i, j int) bool {
-	if d[i].Type != Directory && d[j].Type == Directory {
-		return true
+func GetFiles(path string, config *Config) ([]*DisplayItem, *ColumnsWidth, error) {
+	ignoreRules, err := newIgnoreRules(path, config)
+	if err != nil {
+		return nil, nil, err
 	}
-	if d[i].Type == Directory && d[j].Type != Directory {
-		return false
+
+	listOfFiles, err := readFiles(path, config, ignoreRules)
+	if err != nil && !IsPartial(err) {
+		return nil, nil, err
 	}
 
-	return d[i].Name < d[j].Name
-}
+	sortFiles(listOfFiles, config)
+	listOfFiles = filterFiles(listOfFiles, config)
 
-func (d ByFiles) Swap(i, j int) {
-	d[i], d[j] = d[j], d[i]
+	return listOfFiles, NewColumnsWidth(listOfFiles, config), err
 }
 
-type ColumnsWidth struct {
-	LenPermissions int
-	LenNLinks      int
-	LenUserName    int
-	LenGroupName   int
-	LenSize        int
-	LenModifiedAt  int
-	LenFileName    int
+func WalkFiles(path string, config *Config, fn func(directory *DirectoryListing) error) error {
+	ignoreRules, err := newIgnoreRules(path, config)
+	if err != nil {
+		return err
+	}
+
+	return walkFiles(path, config, ignoreRules, make(map[inodeKey]struct{}), fn)
 }
 
-func newColumnsWidth() *ColumnsWidth {
-	return &ColumnsWidth{
-		LenPermissions: 0,
-		LenNLinks:      0,
-		LenUserName:    0,
-		LenGroupName:   0,
-		LenSize:        0,
-		LenModifiedAt:  0,
-		LenFileName:    0,
+func walkFiles(path string, config *Config, ignoreRules *IgnoreRules, ancestors map[inodeKey]struct{}, fn func(directory *DirectoryListing) error) error {
+	// Following links with --dereference can lead back into a directory that is still being listed.
+	if config.General.Dereference {
+		key, err := directoryKey(path)
+		if err != nil {
+			return fn(&DirectoryListing{Path: path, Err: err})
+		}
+		if _, ok := ancestors[key]; ok {
+			return fn(&DirectoryListing{Path: path, Err: fmt.Errorf("not listing already-listed directory: %s", path)})
+		}
+
+		ancestors[key] = struct{}{}
+		defer delete(ancestors, key)
 	}
-}
 
-func (cw *ColumnsWidth) update(fileInfo fs.FileInfo, stat *syscall.Stat_t, userInfo *user.User, groupInfo *user.Group, config *Config) {
-	if config.Filter.Permissions && len(fileInfo.Mode().String()) > cw.LenPermissions {
-		cw.LenPermissions = len(fileInfo.Mode().String())
+	listOfFiles, readErr := readFiles(path, config, ignoreRules)
+	if readErr != nil && !IsPartial(readErr) {
+		return fn(&DirectoryListing{Path: path, Err: readErr})
 	}
-	if config.Filter.NLinks && len(strconv.Itoa(int(stat.Nlink))) > cw.LenNLinks {
-		cw.LenNLinks = len(strconv.Itoa(int(stat.Nlink)))
+
+	sortFiles(listOfFiles, config)
+
+	subDirectories := make([]string, 0)
+	for _, f := range listOfFiles {
+		if f.Type == Directory && (!f.IsLink() || config.General.Dereference) && !isDotEntry(f.Name) {
+			subDirectories = append(subDirectories, filepath.Join(path, f.Name))
+		}
 	}
-	if config.Filter.UserName && len(userInfo.Username) > cw.LenUserName {
-		cw.LenUserName = len(userInfo.Username)
+
+	listOfFiles = filterFiles(listOfFiles, config)
+
+	err := fn(&DirectoryListing{
+		Path:         path,
+		Files:        listOfFiles,
+		ColumnsWidth: NewColumnsWidth(listOfFiles, config),
+		Err:          readErr,
+	})
+	if err != nil {
+		return err
 	}
-	if config.Filter.GroupName && len(groupInfo.Name) > cw.LenGroupName {
-		cw.LenGroupName = len(groupInfo.Name)
+
+	for _, subDirectory := range subDirectories {
+		subDirectoryIgnoreRules, err := ignoreRules.child(subDirectory)
+		if err != nil {
+			if err = fn(&DirectoryListing{Path: subDirectory, Err: err}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = walkFiles(subDirectory, config, subDirectoryIgnoreRules, ancestors, fn); err != nil {
+			return err
+		}
 	}
-	if config.General.SizeUnit != None && len(SizeFormat(fileInfo.Size(), config.General.SizeUnit)) > cw.LenSize {
-		cw.LenSize = len(SizeFormat(fileInfo.Size(), config.General.SizeUnit))
+
+	return nil
+}
+
+func readFiles(path string, config *Config, ignoreRules *IgnoreRules) (DisplayItems, error) {
+	directory, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
-	if config.Filter.ModificationTime && len(fileInfo.ModTime().Format(config.General.DateFormat)) > cw.LenModifiedAt {
-		cw.LenModifiedAt = len(fileInfo.ModTime().Format(config.General.DateFormat))
+	defer directory.Close()
+
+	errs := make([]error, 0)
+
+	files, err := directory.ReadDir(-1)
+	if err != nil {
+		if len(files) == 0 {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+		errs = append(errs, fmt.Errorf("failed to read directory: %w", err))
 	}
-	if config.Filter.FileName && len(fileInfo.Name()) > cw.LenFileName {
-		cw.LenFileName = len(fileInfo.Name())
+
+	listOfFiles := make(DisplayItems, 0, len(files)+2)
+	if config.Filter.All {
+		dotEntries, err := readDotEntries(path, config)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		listOfFiles = append(listOfFiles, dotEntries...)
 	}
+
+	items, entryErrs := collectDisplayItems(path, visibleEntries(path, files, config, ignoreRules), config)
+	listOfFiles = append(listOfFiles, items...)
+	errs = append(errs, entryErrs...)
+
+	return listOfFiles, newPartialError(errs)
 }
 
-func GetFiles(path string, config *Config) ([]*DisplayItem, *ColumnsWidth, error) {
-	files, err := os.ReadDir(path)
+func visibleEntries(path string, files []fs.DirEntry, config *Config, ignoreRules *IgnoreRules) []fs.DirEntry {
+	return slices.DeleteFunc(files, func(f fs.DirEntry) bool {
+		return isHidden(f.Name(), config) || ignoreRules.isIgnored(filepath.Join(path, f.Name()), f.IsDir())
+	})
+}
+
+func newDisplayItemFromEntry(path string, f fs.DirEntry, config *Config) (*DisplayItem, error) {
+	fileInfo, err := f.Info()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read directory: %w", err)
+		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
 
-	listOfFiles := make(DisplayItems, 0, len(files))
-	columnsWidth := newColumnsWidth()
+	item, err := newDisplayItem(fileInfo.Name(), filepath.Join(path, fileInfo.Name()), fileInfo, config)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, f := range files {
-		fileInfo, err := f.Info()
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get file info: %w", err)
-		}
+	if config.General.TotalSize && item.Type == Directory && !item.IsLink() {
+		usage := DirectoryUsage(item.Path, config)
+		item.Size, item.DiskSize, item.SizeTruncated = usage.Apparent, usage.Disk, usage.Truncated
+	}
 
-		stat, ok := fileInfo.Sys().(*syscall.Stat_t)
-		if !ok {
-			return nil, nil, fmt.Errorf("failed to retrieve file system stats for %s", fileInfo.Name())
-		}
+	return item, nil
+}
 
-		userInfo, err := user.LookupId(strconv.Itoa(int(stat.Uid)))
-		if err != nil {
-			userInfo = &user.User{Username: "Unknown"}
-		}
+func newDisplayItem(name string, path string, fileInfo fs.FileInfo, config *Config) (*DisplayItem, error) {
+	var linkTarget string
+	var linkTargetType FileType
+	var linkStatus LinkStatus
 
-		groupInfo, err := user.LookupGroupId(strconv.Itoa(int(stat.Gid)))
-		if err != nil {
-			groupInfo = &user.Group{Name: "Unknown"}
+	if fileInfo.Mode()&fs.ModeSymlink != 0 {
+		var targetInfo fs.FileInfo
+		linkTarget, linkTargetType, linkStatus, targetInfo = resolveLink(path, fileInfo)
+
+		if config.General.Dereference {
+			if targetInfo == nil {
+				return nil, fmt.Errorf("cannot access %s: %w", path, linkStatus.err())
+			}
+			fileInfo = targetInfo
 		}
+	}
+
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve file system stats for %s", fileInfo.Name())
+	}
+
+	accessTime, changeTime, birthTime := fileTimes(path, fileInfo, stat, needsBirthTime(config))
+
+	var xattrs []Xattr
+	if needsXattrs(config) {
+		xattrs = readXattrs(path, fileInfo, config.Filter.Xattrs)
+	}
+
+	return &DisplayItem{
+		Name:           name,
+		Path:           path,
+		Permissions:    FormatMode(fileInfo.Mode()),
+		UserName:       owners.userName(stat.Uid, config.General.NumericUidGid),
+		GroupName:      owners.groupName(stat.Gid, config.General.NumericUidGid),
+		Size:           fileInfo.Size(),
+		NLinks:         strconv.Itoa(int(stat.Nlink)),
+		Type:           typeOfFile(fileInfo),
+		ModifiedAt:     fileInfo.ModTime().Format(config.General.DateFormat),
+		AccessedAt:     formatTime(accessTime, config),
+		ChangedAt:      formatTime(changeTime, config),
+		BornAt:         formatTime(birthTime, config),
+		Mode:           fileInfo.Mode(),
+		Uid:            stat.Uid,
+		Gid:            stat.Gid,
+		LinkCount:      uint64(stat.Nlink),
+		ModTime:        fileInfo.ModTime(),
+		AccessTime:     accessTime,
+		ChangeTime:     changeTime,
+		BirthTime:      birthTime,
+		DiskSize:       int64(stat.Blocks) * 512,
+		Inode:          uint64(stat.Ino),
+		Blocks:         int64(stat.Blocks),
+		DeviceMajor:    unix.Major(uint64(stat.Rdev)),
+		DeviceMinor:    unix.Minor(uint64(stat.Rdev)),
+		Xattrs:         xattrs,
+		XattrIndicator: xattrIndicator(xattrs),
+
+		LinkTarget:     linkTarget,
+		LinkTargetType: linkTargetType,
+		LinkStatus:     linkStatus,
+	}, nil
+}
+
+func needsBirthTime(config *Config) bool {
+	if config.Filter.BirthTime {
+		return true
+	}
+
+	keys, err := config.General.SortBy.Keys()
+	if err != nil {
+		return false
+	}
 
-		columnsWidth.update(fileInfo, stat, userInfo, groupInfo, config)
-		fileType := typeOfFile(fileInfo)
+	return slices.ContainsFunc(keys, func(key SortKey) bool {
+		return key.Field == SortByBirthTime
+	})
+}
 
-		listOfFiles = append(listOfFiles, &DisplayItem{
-			Name:        fileInfo.Name(),
-			Permissions: fileInfo.Mode().String(),
-			UserName:    userInfo.Username,
-			GroupName:   groupInfo.Name,
-			Size:        fileInfo.Size(),
-			NLinks:      strconv.Itoa(int(stat.Nlink)),
-			Type:        fileType,
-			ModifiedAt:  fileInfo.ModTime().Format(config.General.DateFormat),
-		})
+func formatTime(t time.Time, config *Config) string {
+	if t.IsZero() {
+		return "-"
 	}
 
+	return t.Format(config.General.DateFormat)
+}
+
+func filterFiles(listOfFiles DisplayItems, config *Config) DisplayItems {
 	if config.Filter.OnlyDirs {
 		listOfFiles = listOfFiles.filterDirectories()
 	}
 	if config.Filter.OnlyFiles {
 		listOfFiles = listOfFiles.filterFiles()
 	}
-	if config.General.DirsFirst {
-		sort.Sort(ByDirs(listOfFiles))
-	}
-	if config.General.FilesFirst {
-		sort.Sort(ByFiles(listOfFiles))
+	if config.Filter.OnlySparse {
+		listOfFiles = listOfFiles.filterSparse()
 	}
 
-	return listOfFiles, columnsWidth, nil
+	return listOfFiles
 }
 
 func typeOfFile(fileInfo fs.FileInfo) FileType {
@@ -215,7 +464,7 @@ func typeOfFile(fileInfo fs.FileInfo) FileType {
 	if fileInfo.Mode().Type() == fs.ModeDevice {
 		return BlockDevice
 	}
-	if fileInfo.Mode().Type() == fs.ModeCharDevice {
+	if fileInfo.Mode().Type() == fs.ModeDevice|fs.ModeCharDevice {
 		return CharDevice
 	}
 	if fileInfo.Mode().Type() == fs.ModeSocket {
@@ -225,6 +474,18 @@ func typeOfFile(fileInfo fs.FileInfo) FileType {
 	return NonRegular
 }
 
+func (d *DisplayItem) IsDevice() bool {
+	return d.Type == BlockDevice || d.Type == CharDevice
+}
+
+func FormatDevice(file *DisplayItem) string {
+	if !file.IsDevice() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", file.DeviceMajor, file.DeviceMinor)
+}
+
 func SizeFormat(bytes int64, sizeType SizeType) string {
 	switch sizeType {
 	case None, Bytes:
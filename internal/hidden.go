@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isHidden(name string, config *Config) bool {
+	if config.Filter.All || config.Filter.AlmostAll {
+		return false
+	}
+
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+
+	for _, pattern := range config.Filter.HiddenPatterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDotEntry(name string) bool {
+	return name == "." || name == ".."
+}
+
+func readDotEntries(path string, config *Config) (DisplayItems, error) {
+	listOfFiles := make(DisplayItems, 0, 2)
+	errs := make([]error, 0)
+
+	for _, name := range []string{".", ".."} {
+		fileInfo, err := os.Stat(filepath.Join(path, name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get file info: %w", err))
+			continue
+		}
+
+		item, err := newDisplayItem(name, filepath.Join(path, name), fileInfo, config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		listOfFiles = append(listOfFiles, item)
+	}
+
+	return listOfFiles, errors.Join(errs...)
+}
@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var ignoreFileNames = []string{".gitignore", ".go-lsignore"}
+
+type ignorePattern struct {
+	base     string
+	regexp   *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+type IgnoreRules struct {
+	patterns []*ignorePattern
+}
+
+func newIgnoreRules(path string, config *Config) (*IgnoreRules, error) {
+	if !config.Filter.IgnoreVCS {
+		return nil, nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	directories := []string{absPath}
+	for dir := absPath; ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			directories = []string{absPath}
+			break
+		}
+
+		dir = parent
+		directories = append([]string{dir}, directories...)
+	}
+
+	rules := &IgnoreRules{}
+	for _, dir := range directories {
+		if rules, err = rules.child(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+func (r *IgnoreRules) child(dir string) (*IgnoreRules, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := r.patterns
+	for _, name := range ignoreFileNames {
+		filePatterns, err := readIgnoreFile(filepath.Join(absDir, name), absDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(filePatterns) != 0 {
+			patterns = append(patterns[:len(patterns):len(patterns)], filePatterns...)
+		}
+	}
+
+	return &IgnoreRules{patterns: patterns}, nil
+}
+
+func (r *IgnoreRules) isIgnored(path string, isDir bool) bool {
+	if r == nil {
+		return false
+	}
+	if filepath.Base(path) == ".git" {
+		return true
+	}
+	if len(r.patterns) == 0 {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	isIgnored := false
+	for _, pattern := range r.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(pattern.base, absPath)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+
+		rel = filepath.ToSlash(rel)
+		if !pattern.anchored {
+			rel = filepath.Base(rel)
+		}
+
+		if pattern.regexp.MatchString(rel) {
+			isIgnored = !pattern.negate
+		}
+	}
+
+	return isIgnored
+}
+
+func readIgnoreFile(path string, base string) ([]*ignorePattern, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	defer file.Close()
+
+	patterns := make([]*ignorePattern, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pattern := parseIgnorePattern(scanner.Text(), base)
+		if pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	return patterns, nil
+}
+
+func parseIgnorePattern(line string, base string) *ignorePattern {
+	line = strings.TrimSuffix(line, "\r")
+	if !strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	pattern := &ignorePattern{base: base}
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		pattern.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+
+	expr, err := regexp.Compile(globToRegexp(line))
+	if err != nil {
+		return nil
+	}
+	pattern.regexp = expr
+
+	return pattern
+}
+
+func globToRegexp(glob string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			builder.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case c == '*':
+			builder.WriteString("[^/]*")
+		case c == '?':
+			builder.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				builder.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	builder.WriteString("$")
+
+	return builder.String()
+}
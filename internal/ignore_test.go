@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob    string
+		matches []string
+		misses  []string
+	}{
+		{glob: "*.log", matches: []string{"a.log", ".log"}, misses: []string{"a.log.1", "dir/a.log"}},
+		{glob: "file?.txt", matches: []string{"file1.txt"}, misses: []string{"file.txt", "file10.txt"}},
+		{glob: "**/build", matches: []string{"build", "a/build", "a/b/build"}, misses: []string{"a/builder"}},
+		{glob: "logs/**", matches: []string{"logs/a", "logs/a/b"}, misses: []string{"logs", "other/a"}},
+		{glob: "a/**/b", matches: []string{"a/b", "a/x/b", "a/x/y/b"}, misses: []string{"a/xb"}},
+		{glob: "[abc].go", matches: []string{"a.go", "c.go"}, misses: []string{"d.go"}},
+		{glob: "[!abc].go", matches: []string{"d.go"}, misses: []string{"a.go"}},
+		{glob: `\*.txt`, matches: []string{"*.txt"}, misses: []string{"a.txt"}},
+		{glob: "a+b(c)", matches: []string{"a+b(c)"}, misses: []string{"aab(c)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			expr := regexp.MustCompile(globToRegexp(tt.glob))
+			for _, name := range tt.matches {
+				if !expr.MatchString(name) {
+					t.Errorf("%q does not match %q", tt.glob, name)
+				}
+			}
+			for _, name := range tt.misses {
+				if expr.MatchString(name) {
+					t.Errorf("%q unexpectedly matches %q", tt.glob, name)
+				}
+			}
+		})
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	const base = "/repo"
+
+	newRules := func(lines ...string) *IgnoreRules {
+		rules := &IgnoreRules{}
+		for _, line := range lines {
+			if pattern := parseIgnorePattern(line, base); pattern != nil {
+				rules.patterns = append(rules.patterns, pattern)
+			}
+		}
+		return rules
+	}
+
+	tests := []struct {
+		name  string
+		rules *IgnoreRules
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{name: "disabled", rules: nil, path: "/repo/a.log", want: false},
+		{name: "no patterns", rules: newRules(), path: "/repo/a.log", want: false},
+		{name: "git directory", rules: newRules(), path: "/repo/.git", isDir: true, want: true},
+		{name: "git directory nested", rules: newRules(), path: "/repo/sub/.git", isDir: true, want: true},
+		{name: "comment and blank", rules: newRules("# *.log", ""), path: "/repo/a.log", want: false},
+		{name: "unanchored", rules: newRules("*.log"), path: "/repo/sub/a.log", want: true},
+		{name: "negation", rules: newRules("*.log", "!keep.log"), path: "/repo/keep.log", want: false},
+		{name: "negation order", rules: newRules("!keep.log", "*.log"), path: "/repo/keep.log", want: true},
+		{name: "escaped bang", rules: newRules(`\!important`), path: "/repo/!important", want: true},
+		{name: "double star", rules: newRules("**/build"), path: "/repo/a/b/build", isDir: true, want: true},
+		{name: "anchored match", rules: newRules("/dist"), path: "/repo/dist", isDir: true, want: true},
+		{name: "anchored miss", rules: newRules("/dist"), path: "/repo/sub/dist", isDir: true, want: false},
+		{name: "anchored with slash", rules: newRules("docs/*.md"), path: "/repo/docs/a.md", want: true},
+		{name: "anchored with slash nested", rules: newRules("docs/*.md"), path: "/repo/sub/docs/a.md", want: false},
+		{name: "dir only on directory", rules: newRules("tmp/"), path: "/repo/sub/tmp", isDir: true, want: true},
+		{name: "dir only on file", rules: newRules("tmp/"), path: "/repo/sub/tmp", isDir: false, want: false},
+		{name: "outside base", rules: newRules("*.log"), path: "/other/a.log", want: false},
+		{name: "name starting with dots", rules: newRules("*.log"), path: "/repo/..foo.log", want: true},
+		{name: "directory starting with dots", rules: newRules("/..cache"), path: "/repo/..cache", isDir: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.isIgnored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("isIgnored(%q, %t) = %t, want %t", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+type LinkStatus uint8
+
+const (
+	LinkOK LinkStatus = iota
+	LinkBroken
+	LinkLoop
+)
+
+func (l LinkStatus) String() string {
+	switch l {
+	case LinkBroken:
+		return "broken"
+	case LinkLoop:
+		return "loop"
+	default:
+		return "ok"
+	}
+}
+
+func (l LinkStatus) err() error {
+	switch l {
+	case LinkBroken:
+		return syscall.ENOENT
+	case LinkLoop:
+		return syscall.ELOOP
+	default:
+		return nil
+	}
+}
+
+func directoryKey(path string) (inodeKey, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return inodeKey{}, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return inodeKey{}, fmt.Errorf("failed to retrieve file system stats for %s", fileInfo.Name())
+	}
+
+	return inodeKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, nil
+}
+
+func resolveLink(path string, fileInfo fs.FileInfo) (string, FileType, LinkStatus, fs.FileInfo) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", NonRegular, LinkBroken, nil
+	}
+
+	targetInfo, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, syscall.ELOOP) {
+			return target, NonRegular, LinkLoop, nil
+		}
+		return target, NonRegular, LinkBroken, nil
+	}
+
+	return target, typeOfFile(targetInfo), LinkOK, targetInfo
+}
+
+func (d *DisplayItem) IsLink() bool {
+	return d.LinkTarget != ""
+}
+
+func (d *DisplayItem) DisplayName() string {
+	if d.Type == SymbolicLink && d.LinkTarget != "" {
+		return d.Name + " -> " + d.LinkTarget
+	}
+
+	return d.Name
+}
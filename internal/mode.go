@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+func FormatMode(mode fs.FileMode) string {
+	buf := []byte("----------")
+
+	switch mode.Type() {
+	case fs.ModeDir:
+		buf[0] = 'd'
+	case fs.ModeSymlink:
+		buf[0] = 'l'
+	case fs.ModeNamedPipe:
+		buf[0] = 'p'
+	case fs.ModeSocket:
+		buf[0] = 's'
+	case fs.ModeDevice:
+		buf[0] = 'b'
+	case fs.ModeDevice | fs.ModeCharDevice:
+		buf[0] = 'c'
+	case fs.ModeIrregular:
+		buf[0] = '?'
+	}
+
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buf[i+1] = rwx[i]
+		}
+	}
+
+	setSpecialBit(buf, 3, mode&fs.ModeSetuid != 0, 's')
+	setSpecialBit(buf, 6, mode&fs.ModeSetgid != 0, 's')
+	setSpecialBit(buf, 9, mode&fs.ModeSticky != 0, 't')
+
+	return string(buf)
+}
+
+func FormatOctalMode(mode fs.FileMode) string {
+	return fmt.Sprintf("%04o", ModeBits(mode))
+}
+
+func ModeBits(mode fs.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+
+	return bits
+}
+
+// setSpecialBit marks the execute slot lowercase when the execute bit is also set and uppercase otherwise, as ls does.
+func setSpecialBit(buf []byte, index int, isSet bool, mark byte) {
+	if !isSet {
+		return
+	}
+
+	if buf[index] == 'x' {
+		buf[index] = mark
+	} else {
+		buf[index] = mark - 'a' + 'A'
+	}
+}
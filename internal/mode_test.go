@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestFormatMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode fs.FileMode
+		want string
+	}{
+		{name: "regular", mode: 0o644, want: "-rw-r--r--"},
+		{name: "no permissions", mode: 0, want: "----------"},
+		{name: "all permissions", mode: 0o777, want: "-rwxrwxrwx"},
+		{name: "directory", mode: fs.ModeDir | 0o755, want: "drwxr-xr-x"},
+		{name: "symlink", mode: fs.ModeSymlink | 0o777, want: "lrwxrwxrwx"},
+		{name: "named pipe", mode: fs.ModeNamedPipe | 0o644, want: "prw-r--r--"},
+		{name: "socket", mode: fs.ModeSocket | 0o755, want: "srwxr-xr-x"},
+		{name: "block device", mode: fs.ModeDevice | 0o660, want: "brw-rw----"},
+		{name: "char device", mode: fs.ModeDevice | fs.ModeCharDevice | 0o666, want: "crw-rw-rw-"},
+		{name: "irregular", mode: fs.ModeIrregular | 0o644, want: "?rw-r--r--"},
+		{name: "setuid with execute", mode: fs.ModeSetuid | 0o755, want: "-rwsr-xr-x"},
+		{name: "setuid without execute", mode: fs.ModeSetuid | 0o644, want: "-rwSr--r--"},
+		{name: "setgid with execute", mode: fs.ModeSetgid | 0o755, want: "-rwxr-sr-x"},
+		{name: "setgid without execute", mode: fs.ModeSetgid | 0o644, want: "-rw-r-Sr--"},
+		{name: "sticky with execute", mode: fs.ModeDir | fs.ModeSticky | 0o777, want: "drwxrwxrwt"},
+		{name: "sticky without execute", mode: fs.ModeDir | fs.ModeSticky | 0o776, want: "drwxrwxrwT"},
+		{name: "all special bits", mode: fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky | 0o777, want: "-rwsrwsrwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMode(tt.mode); got != tt.want {
+				t.Errorf("FormatMode(%v) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
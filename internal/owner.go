@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+type ownerCache struct {
+	mu     sync.RWMutex
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+var owners = &ownerCache{
+	users:  make(map[uint32]string),
+	groups: make(map[uint32]string),
+}
+
+func (c *ownerCache) userName(uid uint32, numeric bool) string {
+	id := strconv.FormatUint(uint64(uid), 10)
+	if numeric {
+		return id
+	}
+
+	c.mu.RLock()
+	name, ok := c.users[uid]
+	c.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	name = id
+	if userInfo, err := user.LookupId(id); err == nil {
+		name = userInfo.Username
+	}
+
+	c.mu.Lock()
+	c.users[uid] = name
+	c.mu.Unlock()
+
+	return name
+}
+
+func (c *ownerCache) groupName(gid uint32, numeric bool) string {
+	id := strconv.FormatUint(uint64(gid), 10)
+	if numeric {
+		return id
+	}
+
+	c.mu.RLock()
+	name, ok := c.groups[gid]
+	c.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	name = id
+	if groupInfo, err := user.LookupGroupId(id); err == nil {
+		name = groupInfo.Name
+	}
+
+	c.mu.Lock()
+	c.groups[gid] = name
+	c.mu.Unlock()
+
+	return name
+}
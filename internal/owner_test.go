@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+const benchmarkDirectorySize = 5000
+
+func BenchmarkGetFilesOwners(b *testing.B) {
+	dir := b.TempDir()
+	for i := 0; i < benchmarkDirectorySize; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "file"+strconv.Itoa(i)), nil, 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		config := NewConfig()
+		config.General.NumericUidGid = true
+
+		for i := 0; i < b.N; i++ {
+			files, _, err := GetFiles(dir, config)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for _, f := range files {
+				_, _ = user.LookupId(f.UserName)
+				_, _ = user.LookupGroupId(f.GroupName)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		config := NewConfig()
+
+		for i := 0; i < b.N; i++ {
+			if _, _, err := GetFiles(dir, config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func ExpandPaths(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{"."}, nil
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		if _, err := os.Lstat(arg); err == nil || !strings.ContainsAny(arg, "*?[") {
+			paths = append(paths, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			paths = append(paths, arg)
+			continue
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
+}
+
+// statPath follows a symbolic link given as an argument, unless it is listed in long format without
+// --dereference, in which case the link itself is shown as ls does.
+func statPath(path string, config *Config) (fs.FileInfo, error) {
+	if config.General.Long && !config.General.Dereference {
+		return os.Lstat(path)
+	}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return os.Lstat(path)
+	}
+
+	return fileInfo, nil
+}
+
+func ReadPaths(paths []string, config *Config) ([]*DisplayItem, []string, error) {
+	listOfFiles := make(DisplayItems, 0, len(paths))
+	directories := make([]string, 0, len(paths))
+	errs := make([]error, 0)
+
+	for _, path := range paths {
+		fileInfo, err := statPath(path, config)
+		if err != nil {
+			if _, existsErr := PathExists(path); existsErr != nil {
+				err = existsErr
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+
+		if fileInfo.IsDir() {
+			directories = append(directories, path)
+			continue
+		}
+
+		fileInfo, err = os.Lstat(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get file info: %w", err))
+			continue
+		}
+
+		item, err := newDisplayItem(path, path, fileInfo, config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		listOfFiles = append(listOfFiles, item)
+	}
+
+	sortFiles(listOfFiles, config)
+	sort.Strings(directories)
+
+	return filterFiles(listOfFiles, config), directories, newPartialError(errs)
+}
@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"cmp"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type compareFunc func(a, b *DisplayItem) int
+
+func sortFiles(listOfFiles DisplayItems, config *Config) {
+	compare := comparatorChain(config.General)
+	if compare == nil {
+		return
+	}
+
+	slices.SortStableFunc(listOfFiles, compare)
+}
+
+func IsUnsorted(config *Config) bool {
+	return comparatorChain(config.General) == nil
+}
+
+func comparatorChain(general *General) compareFunc {
+	keys, err := general.SortBy.Keys()
+	if err != nil {
+		keys = []SortKey{{Field: SortByName}}
+	}
+
+	isUnsorted := !slices.ContainsFunc(keys, func(key SortKey) bool {
+		return key.Field != SortByNone
+	})
+	if isUnsorted {
+		return nil
+	}
+
+	compareName := nameComparator(general)
+	chain := make([]compareFunc, 0, len(keys)+3)
+
+	if general.DirsFirst {
+		chain = append(chain, compareDirsFirst)
+	}
+	if general.FilesFirst {
+		chain = append(chain, reversed(compareDirsFirst))
+	}
+
+	for _, key := range keys {
+		if key.Field == SortByNone {
+			continue
+		}
+
+		compare := compareByKey(key.Field, compareName)
+		if key.Reverse != general.Reverse {
+			compare = reversed(compare)
+		}
+		chain = append(chain, compare)
+	}
+
+	tieBreak := compareName
+	if general.Reverse {
+		tieBreak = reversed(tieBreak)
+	}
+	chain = append(chain, tieBreak)
+
+	return func(a, b *DisplayItem) int {
+		for _, compare := range chain {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+}
+
+func compareByKey(field SortBy, compareName compareFunc) compareFunc {
+	switch field {
+	case SortBySize:
+		return func(a, b *DisplayItem) int {
+			return cmp.Compare(b.Size, a.Size)
+		}
+	case SortByTime:
+		return func(a, b *DisplayItem) int {
+			return b.ModTime.Compare(a.ModTime)
+		}
+	case SortByAccessTime:
+		return func(a, b *DisplayItem) int {
+			return b.AccessTime.Compare(a.AccessTime)
+		}
+	case SortByChangeTime:
+		return func(a, b *DisplayItem) int {
+			return b.ChangeTime.Compare(a.ChangeTime)
+		}
+	case SortByBirthTime:
+		return func(a, b *DisplayItem) int {
+			return b.BirthTime.Compare(a.BirthTime)
+		}
+	case SortByExtension:
+		return func(a, b *DisplayItem) int {
+			return cmp.Compare(filepath.Ext(a.Name), filepath.Ext(b.Name))
+		}
+	case SortByType:
+		return func(a, b *DisplayItem) int {
+			return cmp.Compare(a.Type, b.Type)
+		}
+	case SortByDirs:
+		return compareDirsFirst
+	default:
+		return compareName
+	}
+}
+
+func compareDirsFirst(a, b *DisplayItem) int {
+	if a.Type == Directory && b.Type != Directory {
+		return -1
+	}
+	if a.Type != Directory && b.Type == Directory {
+		return 1
+	}
+
+	return 0
+}
+
+func nameComparator(general *General) compareFunc {
+	compareText := textComparator(general)
+
+	return func(a, b *DisplayItem) int {
+		if c := compareText(a.Name, b.Name); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	}
+}
+
+func textComparator(general *General) func(a, b string) int {
+	if general.Collation == CollationLocale {
+		if tag, ok := collationLocale(); ok {
+			options := make([]collate.Option, 0, 1)
+			if general.Natural {
+				options = append(options, collate.Numeric)
+			}
+			return collate.New(tag, options...).CompareString
+		}
+	}
+
+	compare := strings.Compare
+	if general.Natural {
+		compare = compareNatural
+	}
+
+	return func(a, b string) int {
+		return compare(collationKey(a, general.Collation), collationKey(b, general.Collation))
+	}
+}
+
+// collationLocale reads the locale from LC_ALL, LC_COLLATE and LANG. The C and POSIX locales compare bytewise, while
+// an unset or unknown locale uses the root collation order.
+func collationLocale() (language.Tag, bool) {
+	for _, name := range []string{"LC_ALL", "LC_COLLATE", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return language.Und, false
+		}
+
+		tag, err := language.Parse(strings.ReplaceAll(value, "_", "-"))
+		if err != nil {
+			return language.Und, true
+		}
+
+		return tag, true
+	}
+
+	return language.Und, true
+}
+
+func collationKey(name string, collation Collation) string {
+	switch collation {
+	case CollationIgnoreCase:
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
+
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		chunkA, restA := nextChunk(a)
+		chunkB, restB := nextChunk(b)
+
+		var c int
+		if isDigit(chunkA[0]) && isDigit(chunkB[0]) {
+			c = compareNumbers(chunkA, chunkB)
+		} else {
+			c = strings.Compare(chunkA, chunkB)
+		}
+		if c != 0 {
+			return c
+		}
+
+		a, b = restA, restB
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+func nextChunk(s string) (string, string) {
+	digits := isDigit(s[0])
+
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+func compareNumbers(a, b string) int {
+	trimmedA, trimmedB := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(trimmedA), len(trimmedB)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(trimmedA, trimmedB); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func reversed(compare compareFunc) compareFunc {
+	return func(a, b *DisplayItem) int {
+		return compare(b, a)
+	}
+}
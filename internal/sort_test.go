@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareNatural(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "equal", a: "file", b: "file", want: 0},
+		{name: "plain text", a: "abc", b: "abd", want: -1},
+		{name: "numbers by value", a: "file2", b: "file10", want: -1},
+		{name: "versions", a: "v1.9", b: "v1.10", want: -1},
+		{name: "versions reversed", a: "v1.10", b: "v1.9", want: 1},
+		{name: "leading zeros by value", a: "img010", b: "img9", want: 1},
+		{name: "leading zeros tie on length", a: "a1", b: "a01", want: -1},
+		{name: "prefix first", a: "a", b: "a1", want: -1},
+		{name: "digits before letters", a: "1a", b: "a1", want: -1},
+		{name: "large numbers", a: "n99999999999999999999", b: "n100000000000000000000", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareNatural(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareNatural(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollationKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		collation Collation
+		want      string
+	}{
+		{name: "README.md", collation: CollationBytewise, want: "README.md"},
+		{name: "README.md", collation: CollationIgnoreCase, want: "readme.md"},
+		{name: "Éclair", collation: CollationIgnoreCase, want: "éclair"},
+		{name: "_b.txt", collation: CollationIgnoreCase, want: "_b.txt"},
+		{name: "README.md", collation: CollationLocale, want: "README.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.collation)+"/"+tt.name, func(t *testing.T) {
+			if got := collationKey(tt.name, tt.collation); got != tt.want {
+				t.Errorf("collationKey(%q, %s) = %q, want %q", tt.name, tt.collation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameComparator(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		general General
+		names   []string
+		want    []string
+	}{
+		{
+			name:    "bytewise",
+			general: General{Collation: CollationBytewise},
+			names:   []string{"b", "B", "a", "é", "z"},
+			want:    []string{"B", "a", "b", "z", "é"},
+		},
+		{
+			name:    "ignore case",
+			general: General{Collation: CollationIgnoreCase},
+			names:   []string{"b", "B", "a", "C"},
+			want:    []string{"a", "B", "b", "C"},
+		},
+		{
+			name:    "locale",
+			locale:  "fr_FR.UTF-8",
+			general: General{Collation: CollationLocale},
+			names:   []string{"zèbre", "été", "Eve", "abc"},
+			want:    []string{"abc", "été", "Eve", "zèbre"},
+		},
+		{
+			name:    "locale natural",
+			locale:  "en_US.UTF-8",
+			general: General{Collation: CollationLocale, Natural: true},
+			names:   []string{"v1.10", "v1.9", "v1.2"},
+			want:    []string{"v1.2", "v1.9", "v1.10"},
+		},
+		{
+			name:    "C locale",
+			locale:  "C",
+			general: General{Collation: CollationLocale},
+			names:   []string{"é", "z", "a"},
+			want:    []string{"a", "z", "é"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.locale)
+			t.Setenv("LC_COLLATE", "")
+			t.Setenv("LANG", "")
+
+			items := make([]*DisplayItem, 0, len(tt.names))
+			for _, name := range tt.names {
+				items = append(items, &DisplayItem{Name: name})
+			}
+
+			slices.SortStableFunc(items, nameComparator(&tt.general))
+
+			got := make([]string, 0, len(items))
+			for _, item := range items {
+				got = append(got, item.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sorted names = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
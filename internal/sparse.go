@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+func (d *DisplayItem) AllocationRatio() (float64, bool) {
+	if d.Type != Regular || d.Size == 0 || d.SizeTruncated {
+		return 0, false
+	}
+
+	return float64(d.DiskSize) / float64(d.Size), true
+}
+
+func (d *DisplayItem) IsSparse() bool {
+	ratio, ok := d.AllocationRatio()
+
+	return ok && ratio < 1
+}
+
+func FormatSparseness(file *DisplayItem) string {
+	ratio, ok := file.AllocationRatio()
+	if !ok {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.2f", ratio)
+}
+
+func (d DisplayItems) filterSparse() DisplayItems {
+	return slices.DeleteFunc(d, func(item *DisplayItem) bool {
+		return !item.IsSparse()
+	})
+}
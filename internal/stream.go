@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const streamChunkSize = 1024
+
+func StreamFiles(path string, config *Config, fn func(files []*DisplayItem, columnsWidth *ColumnsWidth) error) error {
+	ignoreRules, err := newIgnoreRules(path, config)
+	if err != nil {
+		return err
+	}
+
+	directory, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	defer directory.Close()
+
+	errs := make([]error, 0)
+	columnsWidth := newColumnsWidth()
+	isFirst := true
+
+	listOfFiles := make(DisplayItems, 0, streamChunkSize+2)
+	if config.Filter.All {
+		dotEntries, err := readDotEntries(path, config)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		listOfFiles = append(listOfFiles, dotEntries...)
+	}
+
+	for {
+		files, readErr := directory.ReadDir(streamChunkSize)
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			if isFirst && len(files) == 0 {
+				return fmt.Errorf("failed to read directory: %w", readErr)
+			}
+			errs = append(errs, fmt.Errorf("failed to read directory: %w", readErr))
+		}
+
+		items, entryErrs := collectDisplayItems(path, visibleEntries(path, files, config, ignoreRules), config)
+		errs = append(errs, entryErrs...)
+
+		listOfFiles = filterFiles(append(listOfFiles, items...), config)
+		for _, f := range listOfFiles {
+			columnsWidth.update(f, config)
+		}
+
+		if isFirst || len(listOfFiles) != 0 {
+			if err = fn(listOfFiles, columnsWidth); err != nil {
+				return err
+			}
+			isFirst = false
+		}
+
+		if readErr != nil {
+			break
+		}
+
+		listOfFiles = make(DisplayItems, 0, streamChunkSize)
+	}
+
+	return newPartialError(errs)
+}
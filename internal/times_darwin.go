@@ -0,0 +1,18 @@
+package internal
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func fileTimes(_ string, _ fs.FileInfo, stat *syscall.Stat_t, _ bool) (time.Time, time.Time, time.Time) {
+	accessTime := time.Unix(stat.Atimespec.Unix())
+	changeTime := time.Unix(stat.Ctimespec.Unix())
+
+	if stat.Birthtimespec.Sec == 0 && stat.Birthtimespec.Nsec == 0 {
+		return accessTime, changeTime, time.Time{}
+	}
+
+	return accessTime, changeTime, time.Unix(stat.Birthtimespec.Unix())
+}
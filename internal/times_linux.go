@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"golang.org/x/sys/unix"
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func fileTimes(path string, fileInfo fs.FileInfo, stat *syscall.Stat_t, withBirthTime bool) (time.Time, time.Time, time.Time) {
+	accessTime := time.Unix(stat.Atim.Unix())
+	changeTime := time.Unix(stat.Ctim.Unix())
+
+	if !withBirthTime {
+		return accessTime, changeTime, time.Time{}
+	}
+
+	flags := 0
+	if fileInfo.Mode()&fs.ModeSymlink != 0 {
+		flags = unix.AT_SYMLINK_NOFOLLOW
+	}
+
+	// syscall.Stat_t has no birth time on Linux, statx reports it when the file system records one.
+	var statx unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, flags, unix.STATX_BTIME, &statx); err != nil || statx.Mask&unix.STATX_BTIME == 0 || (statx.Btime.Sec == 0 && statx.Btime.Nsec == 0) {
+		return accessTime, changeTime, time.Time{}
+	}
+
+	return accessTime, changeTime, time.Unix(statx.Btime.Sec, int64(statx.Btime.Nsec))
+}
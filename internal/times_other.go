@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package internal
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func fileTimes(_ string, _ fs.FileInfo, _ *syscall.Stat_t, _ bool) (time.Time, time.Time, time.Time) {
+	return time.Time{}, time.Time{}, time.Time{}
+}
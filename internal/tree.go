@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+type TreeNode struct {
+	*DisplayItem
+	Children []*TreeNode
+}
+
+func GetTree(path string, config *Config, maxDepth int) ([]*TreeNode, *ColumnsWidth, error) {
+	columnsWidth := newColumnsWidth()
+
+	ignoreRules, err := newIgnoreRules(path, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	errs := make([]error, 0)
+
+	nodes, err := readTree(path, config, ignoreRules, maxDepth, 1, columnsWidth, &errs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nodes, columnsWidth, newPartialError(errs)
+}
+
+func readTree(path string, config *Config, ignoreRules *IgnoreRules, maxDepth int, depth int, columnsWidth *ColumnsWidth, errs *[]error) ([]*TreeNode, error) {
+	listOfFiles, err := readFiles(path, config, ignoreRules)
+	if err != nil {
+		var partialError *PartialError
+		if !errors.As(err, &partialError) {
+			return nil, err
+		}
+		*errs = append(*errs, partialError.Errs...)
+	}
+
+	sortFiles(listOfFiles, config)
+	listOfFiles = filterFiles(listOfFiles, config)
+
+	nodes := make([]*TreeNode, 0, len(listOfFiles))
+	for _, f := range listOfFiles {
+		if isDotEntry(f.Name) {
+			continue
+		}
+
+		columnsWidth.update(f, config)
+		node := &TreeNode{DisplayItem: f}
+
+		if f.Type == Directory && !f.IsLink() && (maxDepth <= 0 || depth < maxDepth) {
+			childPath := filepath.Join(path, f.Name)
+
+			childIgnoreRules, err := ignoreRules.child(childPath)
+			if err == nil {
+				node.Children, err = readTree(childPath, config, childIgnoreRules, maxDepth, depth+1, columnsWidth, errs)
+			}
+			if err != nil {
+				*errs = append(*errs, err)
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
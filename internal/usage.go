@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+type Usage struct {
+	Apparent  int64
+	Disk      int64
+	Truncated bool
+}
+
+type inodeKey struct {
+	dev uint64
+	ino uint64
+}
+
+type usageWalker struct {
+	config   *Config
+	seen     map[inodeKey]struct{}
+	maxDepth int
+	deadline time.Time
+	filter   bool
+}
+
+func newUsageWalker(config *Config) *usageWalker {
+	return &usageWalker{
+		config:   config,
+		seen:     make(map[inodeKey]struct{}),
+		maxDepth: config.General.TotalSizeDepth,
+		deadline: config.General.TotalSizeDeadline,
+	}
+}
+
+// DirectoryUsage marks the usage as truncated when part of the tree could not be read, so the size is shown as a
+// lower bound.
+func DirectoryUsage(path string, config *Config) Usage {
+	errs := make([]error, 0)
+
+	usage := newUsageWalker(config).walk(path, 1, nil, &errs)
+	usage.Truncated = usage.Truncated || len(errs) != 0
+
+	return usage
+}
+
+func (w *usageWalker) walk(path string, depth int, ignoreRules *IgnoreRules, errs *[]error) Usage {
+	var usage Usage
+
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("failed to get file info: %w", err))
+		return usage
+	}
+	usage.add(w.usageOf(fileInfo))
+
+	if !fileInfo.IsDir() {
+		return usage
+	}
+	if (w.maxDepth > 0 && depth > w.maxDepth) || w.isExpired() {
+		usage.Truncated = true
+		return usage
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("failed to read directory: %w", err))
+		if len(entries) == 0 {
+			return usage
+		}
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+		if w.isSkipped(entryPath, entry, ignoreRules) {
+			continue
+		}
+
+		if entry.IsDir() {
+			childIgnoreRules, err := ignoreRules.child(entryPath)
+			if err != nil {
+				*errs = append(*errs, err)
+				continue
+			}
+
+			usage.add(w.walk(entryPath, depth+1, childIgnoreRules, errs))
+			continue
+		}
+
+		entryInfo, err := entry.Info()
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("failed to get file info: %w", err))
+			continue
+		}
+		usage.add(w.usageOf(entryInfo))
+	}
+
+	return usage
+}
+
+func (w *usageWalker) usageOf(fileInfo fs.FileInfo) Usage {
+	stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return Usage{Apparent: fileInfo.Size()}
+	}
+
+	if stat.Nlink > 1 && !fileInfo.IsDir() {
+		key := inodeKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+		if _, ok := w.seen[key]; ok {
+			return Usage{}
+		}
+		w.seen[key] = struct{}{}
+	}
+
+	return Usage{Apparent: fileInfo.Size(), Disk: int64(stat.Blocks) * 512}
+}
+
+func (w *usageWalker) isSkipped(path string, entry fs.DirEntry, ignoreRules *IgnoreRules) bool {
+	if !w.filter {
+		return false
+	}
+
+	return isHidden(entry.Name(), w.config) || ignoreRules.isIgnored(path, entry.IsDir())
+}
+
+func (w *usageWalker) isExpired() bool {
+	return !w.deadline.IsZero() && time.Now().After(w.deadline)
+}
+
+func (u *Usage) add(other Usage) {
+	u.Apparent += other.Apparent
+	u.Disk += other.Disk
+	u.Truncated = u.Truncated || other.Truncated
+}
+
+func FormatFileSize(file *DisplayItem, sizeType SizeType) string {
+	if file.IsDevice() {
+		return FormatDevice(file)
+	}
+	if file.SizeTruncated {
+		return ">" + SizeFormat(file.Size, sizeType)
+	}
+
+	return SizeFormat(file.Size, sizeType)
+}
+
+func FormatDiskSize(file *DisplayItem, sizeType SizeType) string {
+	if file.SizeTruncated {
+		return ">" + SizeFormat(file.DiskSize, sizeType)
+	}
+
+	return SizeFormat(file.DiskSize, sizeType)
+}
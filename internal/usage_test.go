@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirectoryUsageTruncated(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "b", "file"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		depth     int
+		deadline  time.Time
+		truncated bool
+	}{
+		{name: "unlimited", truncated: false},
+		{name: "depth within limit", depth: 3, truncated: false},
+		{name: "depth exceeded", depth: 2, truncated: true},
+		{name: "deadline ahead", deadline: time.Now().Add(time.Hour), truncated: false},
+		{name: "deadline passed", deadline: time.Now().Add(-time.Second), truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.General.TotalSizeDepth = tt.depth
+			config.General.TotalSizeDeadline = tt.deadline
+
+			usage := DirectoryUsage(dir, config)
+			if usage.Truncated != tt.truncated {
+				t.Errorf("Truncated = %v, want %v", usage.Truncated, tt.truncated)
+			}
+			if !usage.Truncated && usage.Apparent < 100 {
+				t.Errorf("Apparent = %d, want at least 100", usage.Apparent)
+			}
+		})
+	}
+}
+
+func TestUsageOfHardLinks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+	if err := os.WriteFile(path, make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(path, filepath.Join(dir, "link")); err != nil {
+		t.Skip("hard links are not supported:", err)
+	}
+
+	walker := newUsageWalker(NewConfig())
+	var usage Usage
+	for _, name := range []string{"file", "link"} {
+		fileInfo, err := os.Lstat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		usage.add(walker.usageOf(fileInfo))
+	}
+
+	if usage.Apparent != 100 {
+		t.Errorf("Apparent = %d, want 100", usage.Apparent)
+	}
+}
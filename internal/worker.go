@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"io/fs"
+	"runtime"
+	"sync"
+)
+
+func collectDisplayItems(path string, files []fs.DirEntry, config *Config) (DisplayItems, []error) {
+	items := make([]*DisplayItem, len(files))
+	itemErrs := make([]error, len(files))
+
+	workers := min(concurrency(config), len(files))
+	if workers <= 1 {
+		for i, f := range files {
+			items[i], itemErrs[i] = newDisplayItemFromEntry(path, f, config)
+		}
+	} else {
+		indexes := make(chan int)
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					items[i], itemErrs[i] = newDisplayItemFromEntry(path, files[i], config)
+				}
+			}()
+		}
+
+		for i := range files {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+	}
+
+	listOfFiles := make(DisplayItems, 0, len(files))
+	errs := make([]error, 0)
+	for i, item := range items {
+		if itemErrs[i] != nil {
+			errs = append(errs, itemErrs[i])
+			continue
+		}
+
+		listOfFiles = append(listOfFiles, item)
+	}
+
+	return listOfFiles, errs
+}
+
+func concurrency(config *Config) int {
+	if config.General.Concurrency > 0 {
+		return config.General.Concurrency
+	}
+
+	return runtime.NumCPU()
+}
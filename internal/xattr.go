@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"encoding/hex"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var aclXattrNames = []string{"system.posix_acl_access", "system.posix_acl_default"}
+
+// security.selinux is set on every file of an SELinux system, so it does not mark a file as having extended attributes.
+var ignoredXattrNames = []string{"security.selinux"}
+
+type Xattr struct {
+	Name  string
+	Value string
+}
+
+func xattrIndicator(xattrs []Xattr) string {
+	hasXattrs := false
+	for _, xattr := range xattrs {
+		if slices.Contains(aclXattrNames, xattr.Name) {
+			return "+"
+		}
+		if !slices.Contains(ignoredXattrNames, xattr.Name) {
+			hasXattrs = true
+		}
+	}
+
+	if hasXattrs {
+		return "@"
+	}
+
+	return ""
+}
+
+// needsXattrs reports whether xattrs are shown at all, since reading them costs syscalls for every entry.
+func needsXattrs(config *Config) bool {
+	switch {
+	case config.General.Format != "":
+		return false
+	case config.General.Output == JSON || config.General.Output == NDJSON:
+		return config.Filter.Xattrs
+	case config.General.Output == CSV || config.General.Output == TSV:
+		return false
+	default:
+		return config.General.Long && (config.Filter.Permissions || config.Filter.Xattrs)
+	}
+}
+
+func FormatXattrValue(value string) string {
+	value = strings.TrimRight(value, "\x00")
+
+	isText := utf8.ValidString(value) && strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsPrint(r)
+	}) == -1
+	if isText {
+		return value
+	}
+
+	return "0x" + hex.EncodeToString([]byte(value))
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"bytes"
+	"golang.org/x/sys/unix"
+	"io/fs"
+)
+
+func readXattrs(path string, fileInfo fs.FileInfo, withValues bool) []Xattr {
+	listXattr, getXattr := unix.Listxattr, unix.Getxattr
+	if fileInfo.Mode()&fs.ModeSymlink != 0 {
+		listXattr, getXattr = unix.Llistxattr, unix.Lgetxattr
+	}
+
+	size, err := listXattr(path, nil)
+	if err != nil || size == 0 {
+		return nil
+	}
+
+	names := make([]byte, size)
+	if size, err = listXattr(path, names); err != nil {
+		return nil
+	}
+
+	xattrs := make([]Xattr, 0)
+	for _, name := range bytes.Split(names[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+
+		xattr := Xattr{Name: string(name)}
+		if withValues {
+			xattr.Value = readXattrValue(path, xattr.Name, getXattr)
+		}
+		xattrs = append(xattrs, xattr)
+	}
+
+	return xattrs
+}
+
+func readXattrValue(path string, name string, getXattr func(string, string, []byte) (int, error)) string {
+	size, err := getXattr(path, name, nil)
+	if err != nil || size == 0 {
+		return ""
+	}
+
+	value := make([]byte, size)
+	if size, err = getXattr(path, name, value); err != nil {
+		return ""
+	}
+
+	return string(value[:size])
+}
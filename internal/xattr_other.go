@@ -0,0 +1,9 @@
+//go:build !linux
+
+package internal
+
+import "io/fs"
+
+func readXattrs(_ string, _ fs.FileInfo, _ bool) []Xattr {
+	return nil
+}
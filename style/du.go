@@ -0,0 +1,68 @@
+package style
+
+import (
+	"fmt"
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+const usageBarWidth = 20
+
+func PrintUsageOutput(root *internal.UsageNode, config *internal.Config, isApparent bool) string {
+	total := root.Usage.Size(isApparent)
+
+	lines := []string{formatUsageLine(root, config, total, isApparent, "")}
+	lines = printUsageNodes(lines, root.Children, config, total, isApparent, "")
+
+	return strings.Join(lines, "\n")
+}
+
+func printUsageNodes(lines []string, nodes []*internal.UsageNode, config *internal.Config, total int64, isApparent bool, indent string) []string {
+	for _, node := range nodes {
+		lines = append(lines, formatUsageLine(node, config, total, isApparent, indent+"  "))
+		lines = printUsageNodes(lines, node.Children, config, total, isApparent, indent+"  ")
+	}
+
+	return lines
+}
+
+func formatUsageLine(node *internal.UsageNode, config *internal.Config, total int64, isApparent bool, indent string) string {
+	size := node.Usage.Size(isApparent)
+
+	share := 0.0
+	if total > 0 {
+		share = float64(size) / float64(total)
+	}
+	filled := int(share*usageBarWidth + 0.5)
+
+	bar := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(config.Theme.UsageBar.Foreground)).
+		Background(lipgloss.Color(config.Theme.UsageBar.Background)).
+		Render(strings.Repeat("█", filled)) + strings.Repeat("░", usageBarWidth-filled)
+
+	sizeText := internal.SizeFormat(size, config.General.SizeUnit)
+	if node.Usage.Truncated {
+		sizeText = ">" + sizeText
+	}
+
+	fgColor, bgColor := getFileTypeColor(node.Type, config)
+	name := lipgloss.NewStyle().
+		Foreground(fgColor).
+		Background(bgColor).
+		Render(node.Name)
+
+	return fmt.Sprintf(
+		"   %s  %6.1f%%  %s  %s%s",
+		bar,
+		share*100,
+		lipgloss.NewStyle().
+			Width(10).
+			Align(lipgloss.Right).
+			Foreground(lipgloss.Color(config.Theme.Size.Foreground)).
+			Background(lipgloss.Color(config.Theme.Size.Background)).
+			Render(sizeText),
+		indent,
+		name,
+	)
+}
@@ -0,0 +1,107 @@
+package style
+
+import (
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+const gridMargin = 3
+
+func PrintGridOutput(files []*internal.DisplayItem, config *internal.Config, terminalWidth int, isAcross bool) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	nameWidths := make([]int, len(files))
+	for i, f := range files {
+		nameWidths[i] = lipgloss.Width(f.Name) + gridMargin
+	}
+
+	nCols, nRows, columnWidths := gridDimensions(nameWidths, terminalWidth, isAcross)
+
+	lines := make([]string, 0, nRows)
+	for row := 0; row < nRows; row++ {
+		var line strings.Builder
+		for col := 0; col < nCols; col++ {
+			i := gridIndex(row, col, nRows, nCols, isAcross)
+			if i >= len(files) {
+				continue
+			}
+
+			width := columnWidths[col]
+			if col == nCols-1 || gridIndex(row, col+1, nRows, nCols, isAcross) >= len(files) {
+				width = nameWidths[i]
+			}
+			line.WriteString(renderGridCell(files[i], config, width))
+		}
+
+		lines = append(lines, line.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func PrintOnePerLineOutput(files []*internal.DisplayItem, config *internal.Config) string {
+	lines := make([]string, 0, len(files))
+	for _, f := range files {
+		lines = append(lines, PrintShortOutput(f, config))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func gridDimensions(nameWidths []int, terminalWidth int, isAcross bool) (int, int, []int) {
+	// Every column is at least one character plus the margin wide, which bounds the search on large directories.
+	maxCols := min(len(nameWidths), terminalWidth/(gridMargin+1))
+
+	for nCols := maxCols; nCols > 1; nCols-- {
+		nRows := (len(nameWidths) + nCols - 1) / nCols
+		if !isAcross && (nCols-1)*nRows >= len(nameWidths) {
+			continue
+		}
+
+		columnWidths := make([]int, nCols)
+		for i, width := range nameWidths {
+			col := i / nRows
+			if isAcross {
+				col = i % nCols
+			}
+			columnWidths[col] = max(columnWidths[col], width)
+		}
+
+		totalWidth := 0
+		for _, width := range columnWidths {
+			totalWidth += width
+		}
+		if totalWidth <= terminalWidth {
+			return nCols, nRows, columnWidths
+		}
+	}
+
+	maxWidth := 0
+	for _, width := range nameWidths {
+		maxWidth = max(maxWidth, width)
+	}
+
+	return 1, len(nameWidths), []int{maxWidth}
+}
+
+func gridIndex(row, col, nRows, nCols int, isAcross bool) int {
+	if isAcross {
+		return row*nCols + col
+	}
+
+	return col*nRows + row
+}
+
+func renderGridCell(file *internal.DisplayItem, config *internal.Config, width int) string {
+	fgColor, bgColor := getFileColor(file, config)
+
+	name := lipgloss.NewStyle().
+		Foreground(fgColor).
+		Background(bgColor).
+		Render(file.Name)
+
+	return strings.Repeat(" ", gridMargin) + name + strings.Repeat(" ", max(width-gridMargin-lipgloss.Width(file.Name), 0))
+}
@@ -3,10 +3,12 @@ package style
 import (
 	"github.com/CezaryMackowski/go-ls/internal"
 	"github.com/charmbracelet/lipgloss"
+	"strconv"
+	"strings"
 )
 
 func PrintShortOutput(file *internal.DisplayItem, config *internal.Config) string {
-	fgColor, bgColor := getFileTypeColor(file.Type, config)
+	fgColor, bgColor := getFileColor(file, config)
 
 	fileName := lipgloss.NewStyle().
 		Width(len(file.Name)).
@@ -20,11 +22,32 @@ func PrintShortOutput(file *internal.DisplayItem, config *internal.Config) strin
 }
 
 func PrintLongOutput(file *internal.DisplayItem, config *internal.Config, columnsWidth *internal.ColumnsWidth) string {
-	var permissions, nLinks, user, group, size, modifiedAt, fileName string
+	var inode, blocks, permissions, octalPermissions, nLinks, user, group, size, diskSize, sparseness, device, modifiedAt, accessedAt, changedAt, bornAt, fileName string
 
+	if columnsWidth.LenInode != 0 {
+		inode = formatCommonColumn(
+			strconv.FormatUint(file.Inode, 10),
+			columnsWidth.LenInode+2,
+			lipgloss.Color(config.Theme.Inode.Foreground),
+			lipgloss.Color(config.Theme.Inode.Background),
+			lipgloss.Right,
+		)
+	}
+	if columnsWidth.LenBlocks != 0 {
+		blocks = formatCommonColumn(
+			strconv.FormatInt(file.Blocks, 10),
+			columnsWidth.LenBlocks+2,
+			lipgloss.Color(config.Theme.Size.Foreground),
+			lipgloss.Color(config.Theme.Size.Background),
+			lipgloss.Right,
+		)
+	}
 	if columnsWidth.LenPermissions != 0 {
 		permissions = formatPermissions(file, config, columnsWidth.LenPermissions)
 	}
+	if columnsWidth.LenOctalPermissions != 0 {
+		octalPermissions = formatOctalPermissions(file, config, columnsWidth.LenOctalPermissions)
+	}
 	if columnsWidth.LenNLinks != 0 {
 		nLinks = formatCommonColumn(
 			file.NLinks,
@@ -54,13 +77,40 @@ func PrintLongOutput(file *internal.DisplayItem, config *internal.Config, column
 	}
 	if columnsWidth.LenSize != 0 {
 		size = formatCommonColumn(
-			internal.SizeFormat(file.Size, config.General.SizeUnit),
+			internal.FormatFileSize(file, config.General.SizeUnit),
 			columnsWidth.LenSize+2,
 			lipgloss.Color(config.Theme.Size.Foreground),
 			lipgloss.Color(config.Theme.Size.Background),
 			lipgloss.Right,
 		)
 	}
+	if columnsWidth.LenDiskSize != 0 {
+		diskSize = formatCommonColumn(
+			internal.FormatDiskSize(file, config.General.SizeUnit),
+			columnsWidth.LenDiskSize+2,
+			lipgloss.Color(config.Theme.Size.Foreground),
+			lipgloss.Color(config.Theme.Size.Background),
+			lipgloss.Right,
+		)
+	}
+	if columnsWidth.LenSparseness != 0 {
+		sparseness = formatCommonColumn(
+			internal.FormatSparseness(file),
+			columnsWidth.LenSparseness+2,
+			lipgloss.Color(config.Theme.Size.Foreground),
+			lipgloss.Color(config.Theme.Size.Background),
+			lipgloss.Right,
+		)
+	}
+	if columnsWidth.LenDevice != 0 {
+		device = formatCommonColumn(
+			internal.FormatDevice(file),
+			columnsWidth.LenDevice+2,
+			lipgloss.Color(config.Theme.Size.Foreground),
+			lipgloss.Color(config.Theme.Size.Background),
+			lipgloss.Right,
+		)
+	}
 	if columnsWidth.LenModifiedAt != 0 {
 		modifiedAt = formatCommonColumn(
 			file.ModifiedAt,
@@ -70,65 +120,145 @@ func PrintLongOutput(file *internal.DisplayItem, config *internal.Config, column
 			lipgloss.Left,
 		)
 	}
-	if columnsWidth.LenFileName != 0 {
-		fgColor, bgColor := getFileTypeColor(file.Type, config)
-
-		fileName = formatCommonColumn(
-			file.Name,
-			columnsWidth.LenFileName+2,
-			fgColor,
-			bgColor,
+	if columnsWidth.LenAccessedAt != 0 {
+		accessedAt = formatCommonColumn(
+			file.AccessedAt,
+			columnsWidth.LenAccessedAt+2,
+			lipgloss.Color(config.Theme.AccessTime.Foreground),
+			lipgloss.Color(config.Theme.AccessTime.Background),
+			lipgloss.Left,
+		)
+	}
+	if columnsWidth.LenChangedAt != 0 {
+		changedAt = formatCommonColumn(
+			file.ChangedAt,
+			columnsWidth.LenChangedAt+2,
+			lipgloss.Color(config.Theme.ChangeTime.Foreground),
+			lipgloss.Color(config.Theme.ChangeTime.Background),
+			lipgloss.Left,
+		)
+	}
+	if columnsWidth.LenBornAt != 0 {
+		bornAt = formatCommonColumn(
+			file.BornAt,
+			columnsWidth.LenBornAt+2,
+			lipgloss.Color(config.Theme.BirthTime.Foreground),
+			lipgloss.Color(config.Theme.BirthTime.Background),
 			lipgloss.Left,
 		)
 	}
+	if columnsWidth.LenFileName != 0 {
+		fileName = lipgloss.NewStyle().
+			Width(columnsWidth.LenFileName + 2).
+			Align(lipgloss.Left).
+			MarginLeft(3).
+			Render(renderFileName(file, config))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Left, inode, blocks, permissions, octalPermissions, nLinks, user, group, size, diskSize, sparseness, device, modifiedAt, accessedAt, changedAt, bornAt, fileName)
+}
+
+func PrintXattrOutput(file *internal.DisplayItem, config *internal.Config) string {
+	lines := make([]string, 0, len(file.Xattrs))
+	for _, xattr := range file.Xattrs {
+		name := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(config.Theme.Permissions.XattrColor.Foreground)).
+			Background(lipgloss.Color(config.Theme.Permissions.XattrColor.Background)).
+			Render(xattr.Name)
 
-	return lipgloss.JoinHorizontal(lipgloss.Left, permissions, nLinks, user, group, size, modifiedAt, fileName)
+		lines = append(lines, lipgloss.NewStyle().
+			MarginLeft(5).
+			Render(name+": "+internal.FormatXattrValue(xattr.Value)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 func formatPermissions(file *internal.DisplayItem, config *internal.Config, columnWidth int) string {
+	permissions := config.Theme.Permissions
+	colors := []internal.Color{
+		permissions.OwnerReadColor, permissions.OwnerWriteColor, permissions.OwnerExecuteColor,
+		permissions.GroupReadColor, permissions.GroupWriteColor, permissions.GroupExecuteColor,
+		permissions.OthersReadColor, permissions.OthersWriteColor, permissions.OthersExecuteColor,
+	}
+
+	fgColor, bgColor := getFileTypeColor(file.Type, config)
+	mode := []string{lipgloss.NewStyle().Foreground(fgColor).Background(bgColor).Render(file.Permissions[:1])}
+
+	for i, color := range colors {
+		char := file.Permissions[i+1]
+		switch char {
+		case 's', 'S':
+			color = permissions.SetuidColor
+			if i == 5 {
+				color = permissions.SetgidColor
+			}
+		case 't', 'T':
+			color = permissions.StickyColor
+		}
+
+		mode = append(mode, lipgloss.NewStyle().
+			Foreground(lipgloss.Color(color.Foreground)).
+			Background(lipgloss.Color(color.Background)).
+			Render(string(char)))
+	}
+
+	if indicator := file.XattrIndicator; indicator != "" {
+		mode = append(mode, lipgloss.NewStyle().
+			Foreground(lipgloss.Color(permissions.XattrColor.Foreground)).
+			Background(lipgloss.Color(permissions.XattrColor.Background)).
+			Render(indicator))
+	}
+
+	// Padding is added by hand since lipgloss trims trailing spaces, which would misalign entries without an xattr indicator.
+	padding := strings.Repeat(" ", columnWidth-len(file.Permissions)-len(file.XattrIndicator))
+
+	return "  " + lipgloss.JoinHorizontal(lipgloss.Right, mode...) + padding
+}
+
+func formatOctalPermissions(file *internal.DisplayItem, config *internal.Config, columnWidth int) string {
+	permissions := config.Theme.Permissions
+	octalMode := internal.FormatOctalMode(file.Mode)
+	bits := internal.ModeBits(file.Mode)
+
+	colors := []internal.Color{
+		specialBitsColor(bits>>9, permissions),
+		permissionBitsColor(bits>>6, permissions.OwnerReadColor, permissions.OwnerWriteColor, permissions.OwnerExecuteColor, permissions.EmptyColor),
+		permissionBitsColor(bits>>3, permissions.GroupReadColor, permissions.GroupWriteColor, permissions.GroupExecuteColor, permissions.EmptyColor),
+		permissionBitsColor(bits, permissions.OthersReadColor, permissions.OthersWriteColor, permissions.OthersExecuteColor, permissions.EmptyColor),
+	}
+
+	digits := make([]string, 0, len(colors))
+	for i, color := range colors {
+		digits = append(digits, lipgloss.NewStyle().
+			Foreground(lipgloss.Color(color.Foreground)).
+			Background(lipgloss.Color(color.Background)).
+			Render(octalMode[i:i+1]))
+	}
+
 	return lipgloss.NewStyle().
 		Width(columnWidth + 2).
 		Align(lipgloss.Right).
-		Render(lipgloss.JoinHorizontal(
-			lipgloss.Right,
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OwnerReadColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OwnerReadColor.Background)).
-				Render(string(file.Permissions[1])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OwnerWriteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OwnerWriteColor.Background)).
-				Render(string(file.Permissions[2])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OwnerExecuteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OwnerExecuteColor.Background)).
-				Render(string(file.Permissions[3])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.GroupReadColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.GroupReadColor.Background)).
-				Render(string(file.Permissions[4])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.GroupWriteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.GroupWriteColor.Background)).
-				Render(string(file.Permissions[5])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.GroupExecuteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.GroupExecuteColor.Background)).
-				Render(string(file.Permissions[6])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OthersReadColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OthersReadColor.Background)).
-				Render(string(file.Permissions[7])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OthersWriteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OthersWriteColor.Background)).
-				Render(string(file.Permissions[8])),
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color(config.Theme.Permissions.OthersExecuteColor.Foreground)).
-				Background(lipgloss.Color(config.Theme.Permissions.OthersExecuteColor.Background)).
-				Render(string(file.Permissions[9])),
-		),
-		)
+		MarginLeft(3).
+		Render(lipgloss.JoinHorizontal(lipgloss.Right, digits...))
+}
+
+// permissionBitsColor picks the color of the most significant bit set in an rwx digit.
+func permissionBitsColor(bits uint32, read, write, execute, empty internal.Color) internal.Color {
+	switch {
+	case bits&4 != 0:
+		return read
+	case bits&2 != 0:
+		return write
+	case bits&1 != 0:
+		return execute
+	default:
+		return empty
+	}
+}
+
+func specialBitsColor(bits uint32, permissions *internal.Permissions) internal.Color {
+	return permissionBitsColor(bits, permissions.SetuidColor, permissions.SetgidColor, permissions.StickyColor, permissions.EmptyColor)
 }
 
 func formatCommonColumn(text string, width int, fgColor lipgloss.Color, bgColor lipgloss.Color, align lipgloss.Position) string {
@@ -141,6 +271,45 @@ func formatCommonColumn(text string, width int, fgColor lipgloss.Color, bgColor
 		Render(text)
 }
 
+func renderFileName(file *internal.DisplayItem, config *internal.Config) string {
+	fgColor, bgColor := getFileColor(file, config)
+	fileName := lipgloss.NewStyle().
+		Foreground(fgColor).
+		Background(bgColor).
+		Render(file.Name)
+
+	if file.Type != internal.SymbolicLink || file.LinkTarget == "" {
+		return fileName
+	}
+
+	fgColor, bgColor = getLinkTargetColor(file, config)
+	linkTarget := lipgloss.NewStyle().
+		Foreground(fgColor).
+		Background(bgColor).
+		Render(file.LinkTarget)
+
+	return fileName + " -> " + linkTarget
+}
+
+func getFileColor(file *internal.DisplayItem, config *internal.Config) (lipgloss.Color, lipgloss.Color) {
+	if file.Type == internal.SymbolicLink && file.LinkStatus != internal.LinkOK {
+		return getLinkTargetColor(file, config)
+	}
+
+	return getFileTypeColor(file.Type, config)
+}
+
+func getLinkTargetColor(file *internal.DisplayItem, config *internal.Config) (lipgloss.Color, lipgloss.Color) {
+	switch file.LinkStatus {
+	case internal.LinkBroken:
+		return lipgloss.Color(config.Theme.FileName.BrokenLinkColor.Foreground), lipgloss.Color(config.Theme.FileName.BrokenLinkColor.Background)
+	case internal.LinkLoop:
+		return lipgloss.Color(config.Theme.FileName.LinkLoopColor.Foreground), lipgloss.Color(config.Theme.FileName.LinkLoopColor.Background)
+	default:
+		return getFileTypeColor(file.LinkTargetType, config)
+	}
+}
+
 func getFileTypeColor(fileType internal.FileType, config *internal.Config) (lipgloss.Color, lipgloss.Color) {
 	var fgColor, bgColor lipgloss.Color
 
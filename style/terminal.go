@@ -0,0 +1,27 @@
+package style
+
+import (
+	"golang.org/x/sys/unix"
+	"os"
+	"strconv"
+)
+
+const defaultTerminalWidth = 80
+
+func IsTerminal() bool {
+	_, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	return err == nil
+}
+
+func TerminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	winSize, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || winSize.Col == 0 {
+		return defaultTerminalWidth
+	}
+
+	return int(winSize.Col)
+}
@@ -0,0 +1,42 @@
+package style
+
+import (
+	"github.com/CezaryMackowski/go-ls/internal"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	treeBranch     = "├── "
+	treeLastBranch = "└── "
+	treeIndent     = "│   "
+	treeLastIndent = "    "
+)
+
+func PrintTreeOutput(root string, nodes []*internal.TreeNode, config *internal.Config, columnsWidth *internal.ColumnsWidth, isLong bool) string {
+	fgColor, bgColor := getFileTypeColor(internal.Directory, config)
+	lines := []string{lipgloss.NewStyle().Foreground(fgColor).Background(bgColor).Render(root)}
+
+	prefixColumnsWidth := *columnsWidth
+	prefixColumnsWidth.LenFileName = 0
+
+	return lipgloss.JoinVertical(lipgloss.Left, printTreeNodes(lines, nodes, "", config, &prefixColumnsWidth, isLong)...)
+}
+
+func printTreeNodes(lines []string, nodes []*internal.TreeNode, indent string, config *internal.Config, columnsWidth *internal.ColumnsWidth, isLong bool) []string {
+	for i, node := range nodes {
+		branch, childIndent := treeBranch, treeIndent
+		if i == len(nodes)-1 {
+			branch, childIndent = treeLastBranch, treeLastIndent
+		}
+
+		var prefix string
+		if isLong {
+			prefix = PrintLongOutput(node.DisplayItem, config, columnsWidth) + "   "
+		}
+
+		lines = append(lines, prefix+indent+branch+renderFileName(node.DisplayItem, config))
+		lines = printTreeNodes(lines, node.Children, indent+childIndent, config, columnsWidth, isLong)
+	}
+
+	return lines
+}